@@ -0,0 +1,219 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/actions-go/toolkit/core"
+	"github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+)
+
+// ChecksumsFile is the asset listing the SHA-256 checksums of the other
+// assets, in the format of sha256sum.
+const ChecksumsFile = "SHA256SUMS"
+
+// assetUploadRetries is how often a failed asset upload is retried.
+const assetUploadRetries = 3
+
+var ErrNoAssetsMatched = errors.New("no files match asset pattern")
+
+// ParseAssets parses the newline separated assets input.
+func ParseAssets(input string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(input, "\n") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+func validateAssets(actionConfig ActionConfig) error {
+	switch {
+	case len(actionConfig.Assets) == 0:
+		return nil
+	case len(actionConfig.Components) > 0:
+		return errors.New("assets: not supported with components")
+	case actionConfig.ReleaseStrategy != ReleaseStrategyRelease:
+		return fmt.Errorf("assets: need release_strategy %s", ReleaseStrategyRelease)
+	case actionConfig.Backend == BackendGit:
+		return errors.New("assets: not supported by the git backend")
+	}
+	var errs []error
+	for i, pattern := range actionConfig.Assets {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("assets[%d]: %q: %w", i, pattern, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// resolveAssets returns the files matching patterns, relative to dir. Every
+// pattern has to match a file, and the files become assets named after
+// their base names, which have to be unique.
+func resolveAssets(dir string, patterns []string) ([]string, error) {
+	var paths []string
+	byName := map[string]string{}
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		var matched bool
+		for _, path := range matches {
+			if info, err := os.Stat(path); err != nil || info.IsDir() {
+				continue
+			}
+			matched = true
+			name := filepath.Base(path)
+			if other, ok := byName[name]; ok {
+				if other != path {
+					return nil, fmt.Errorf("assets: %s and %s are both named %s", other, path, name)
+				}
+				continue
+			}
+			byName[name] = path
+			paths = append(paths, path)
+		}
+		if !matched {
+			return nil, fmt.Errorf("%w: %s", ErrNoAssetsMatched, pattern)
+		}
+	}
+	return paths, nil
+}
+
+// writeChecksums writes the ChecksumsFile of paths to dir and returns its
+// path.
+func writeChecksums(dir string, paths []string) (string, error) {
+	sorted := append([]string(nil), paths...)
+	sort.Slice(sorted, func(i, j int) bool { return filepath.Base(sorted[i]) < filepath.Base(sorted[j]) })
+	var content strings.Builder
+	for _, path := range sorted {
+		sum, err := sha256File(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&content, "%s  %s\n", sum, filepath.Base(path))
+	}
+	checksums := filepath.Join(dir, ChecksumsFile)
+	return checksums, os.WriteFile(checksums, []byte(content.String()), 0o644)
+}
+
+func sha256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// executeAssets uploads the files matching the asset patterns, and their
+// ChecksumsFile, to release. Assets of the same name, e.g. from an earlier
+// run of a re-run workflow, are replaced.
+func executeAssets(ghActionIface utils.GithubActionIface, actionConfig ActionConfig, release *github.RepositoryRelease) error {
+	if len(actionConfig.Assets) == 0 || release == nil {
+		return nil
+	}
+	paths, err := resolveAssets(actionConfig.GitDir, actionConfig.Assets)
+	if err != nil {
+		return err
+	}
+	if actionConfig.AssetChecksums {
+		for _, path := range paths {
+			if filepath.Base(path) == ChecksumsFile {
+				return fmt.Errorf("assets: %s is named like the checksums asset", path)
+			}
+		}
+		dir, err := os.MkdirTemp("", "semver-sugar-assets-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		checksums, err := writeChecksums(dir, paths)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, checksums)
+	}
+
+	for _, path := range paths {
+		if err := uploadAsset(ghActionIface, release.GetID(), path, assetUploadRetries); err != nil {
+			return err
+		}
+		core.Infof("Uploaded %s to release %s", filepath.Base(path), actionConfig.NextTag)
+	}
+	return nil
+}
+
+// uploadAsset uploads the file at path to the release with the ID
+// releaseID, up to maxRetries times again when the upload fails with a
+// transient error. The existing asset of the same name, which may be what a
+// failed upload left behind, is deleted before every attempt.
+func uploadAsset(ghActionIface utils.GithubActionIface, releaseID int64, path string, maxRetries int) error {
+	name := filepath.Base(path)
+	for retry := 1; ; retry++ {
+		err := deleteAsset(ghActionIface, releaseID, name)
+		if err == nil {
+			_, err = ghActionIface.UploadReleaseAsset(releaseID, name, path)
+		}
+		if err == nil {
+			return nil
+		}
+		if !isTransient(err) || retry > maxRetries {
+			return fmt.Errorf("uploading asset %s: %w", name, err)
+		}
+		core.Warningf("uploading asset %s: %s, retrying (%d/%d)", name, err, retry, maxRetries)
+		time.Sleep(time.Duration(retry) * retryDelay)
+	}
+}
+
+// isTransient reports whether err may go away when trying again: a server
+// error, a secondary rate limit or a failed connection. Other responses,
+// e.g. 4xx ones, fail the same way again.
+func isTransient(err error) bool {
+	var errorResponse *github.ErrorResponse
+	if errors.As(err, &errorResponse) {
+		return errorResponse.Response != nil && errorResponse.Response.StatusCode >= http.StatusInternalServerError
+	}
+	var abuseRateLimitError *github.AbuseRateLimitError
+	if errors.As(err, &abuseRateLimitError) {
+		return true
+	}
+	var netError net.Error
+	return errors.As(err, &netError)
+}
+
+// deleteAsset deletes the asset called name of the release with the ID
+// releaseID if there is one.
+func deleteAsset(ghActionIface utils.GithubActionIface, releaseID int64, name string) error {
+	assets, err := ghActionIface.ListReleaseAssets(releaseID)
+	if err != nil {
+		return err
+	}
+	for _, asset := range assets {
+		if asset.GetName() == name {
+			core.Infof("Replacing asset %s", name)
+			return ghActionIface.DeleteReleaseAsset(asset.GetID())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	github "github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeAssets writes files named after the keys of files to dir.
+func writeAssets(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestParseAssets(t *testing.T) {
+	assert.Equal(t, []string{"dist/*.tar.gz", "dist/*.zip"}, ParseAssets("dist/*.tar.gz\n  dist/*.zip\n\n"))
+	assert.Empty(t, ParseAssets(" \n"))
+}
+
+func TestValidateAssets(t *testing.T) {
+	actionConfig := ActionConfig{ReleaseStrategy: ReleaseStrategyRelease, Backend: BackendGithub, Assets: []string{"dist/*"}}
+	assert.NoError(t, validateAssets(actionConfig))
+
+	invalid := actionConfig
+	invalid.Assets = []string{"dist/[a-"}
+	assert.ErrorContains(t, validateAssets(invalid), `assets[0]: "dist/[a-"`)
+
+	tag := actionConfig
+	tag.ReleaseStrategy = ReleaseStrategyTag
+	assert.ErrorContains(t, validateAssets(tag), "need release_strategy release")
+
+	git := actionConfig
+	git.Backend = BackendGit
+	assert.ErrorContains(t, validateAssets(git), "not supported by the git backend")
+
+	components := actionConfig
+	components.Components = []Component{{Name: "api", Path: "api"}}
+	assert.ErrorContains(t, validateAssets(components), "not supported with components")
+}
+
+func TestResolveAssets(t *testing.T) {
+	dir := t.TempDir()
+	writeAssets(t, dir, map[string]string{
+		"dist/app-linux.tar.gz":  "linux",
+		"dist/app-darwin.tar.gz": "darwin",
+		"dist/app.zip":           "zip",
+		"other/app.zip":          "other zip",
+	})
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "dist", "dir.tar.gz"), 0o755))
+
+	paths, err := resolveAssets(dir, []string{"dist/*.tar.gz", "dist/app.zip", "dist/*.zip"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "dist/app-darwin.tar.gz"),
+		filepath.Join(dir, "dist/app-linux.tar.gz"),
+		filepath.Join(dir, "dist/app.zip"),
+	}, paths)
+
+	_, err = resolveAssets(dir, []string{"dist/*.deb"})
+	assert.ErrorIs(t, err, ErrNoAssetsMatched)
+
+	_, err = resolveAssets(dir, []string{"*/app.zip"})
+	assert.ErrorContains(t, err, "are both named app.zip")
+}
+
+func TestWriteChecksums(t *testing.T) {
+	dir := t.TempDir()
+	writeAssets(t, dir, map[string]string{"b.txt": "b\n", "a.txt": "a\n"})
+
+	checksums, err := writeChecksums(t.TempDir(), []string{filepath.Join(dir, "b.txt"), filepath.Join(dir, "a.txt")})
+	require.NoError(t, err)
+	assert.Equal(t, ChecksumsFile, filepath.Base(checksums))
+	content, err := os.ReadFile(checksums)
+	require.NoError(t, err)
+	assert.Equal(t, "87428fc522803d31065e7bce3cf03fe475096631e5e07bbd7a0fde60c4cf25c7  a.txt\n"+
+		"0263829989b6fd954f72baaf2fc64bc2e2f01d692d4de72986ea808f6e99813f  b.txt\n", string(content))
+}
+
+func TestExecuteAssets(t *testing.T) {
+	retryDelay = 0
+	dir := t.TempDir()
+	writeAssets(t, dir, map[string]string{"dist/app.tar.gz": "app"})
+	release := &github.RepositoryRelease{ID: github.Int64(1)}
+	actionConfig := ActionConfig{
+		NextTag:        "v1.1.0",
+		GitDir:         dir,
+		Assets:         []string{"dist/*.tar.gz"},
+		AssetChecksums: true,
+	}
+	uploaded := &github.ReleaseAsset{ID: github.Int64(3)}
+	connectionReset := &url.Error{Op: "Post", URL: "https://uploads.github.com", Err: errors.New("connection reset")}
+	unprocessable := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusUnprocessableEntity}, Message: "Validation Failed"}
+
+	tests := []struct {
+		name          string
+		setupMock     func(mockGHActionIface *utils.MockGithubActionIface)
+		expectedError string
+	}{
+		{
+			name: "Upload with checksums",
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().ListReleaseAssets(int64(1)).Return(nil, nil).Times(2)
+				mockGHActionIface.EXPECT().UploadReleaseAsset(int64(1), "app.tar.gz", filepath.Join(dir, "dist/app.tar.gz")).Return(uploaded, nil)
+				mockGHActionIface.EXPECT().UploadReleaseAsset(int64(1), ChecksumsFile, gomock.Any()).Return(uploaded, nil)
+			},
+		},
+		{
+			name: "Replace existing asset and retry",
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				existing := []*github.ReleaseAsset{{ID: github.Int64(2), Name: github.String("app.tar.gz")}}
+				gomock.InOrder(
+					mockGHActionIface.EXPECT().ListReleaseAssets(int64(1)).Return(existing, nil),
+					mockGHActionIface.EXPECT().DeleteReleaseAsset(int64(2)).Return(nil),
+					mockGHActionIface.EXPECT().UploadReleaseAsset(int64(1), "app.tar.gz", gomock.Any()).Return(nil, connectionReset),
+					mockGHActionIface.EXPECT().ListReleaseAssets(int64(1)).Return(nil, nil),
+					mockGHActionIface.EXPECT().UploadReleaseAsset(int64(1), "app.tar.gz", gomock.Any()).Return(uploaded, nil),
+					mockGHActionIface.EXPECT().ListReleaseAssets(int64(1)).Return(nil, nil),
+					mockGHActionIface.EXPECT().UploadReleaseAsset(int64(1), ChecksumsFile, gomock.Any()).Return(uploaded, nil),
+				)
+			},
+		},
+		{
+			name: "Upload keeps failing",
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().ListReleaseAssets(int64(1)).Return(nil, nil).Times(assetUploadRetries + 1)
+				mockGHActionIface.EXPECT().UploadReleaseAsset(int64(1), "app.tar.gz", gomock.Any()).Return(nil, connectionReset).Times(assetUploadRetries + 1)
+			},
+			expectedError: `uploading asset app.tar.gz: Post "https://uploads.github.com": connection reset`,
+		},
+		{
+			name: "Rejected upload is not retried",
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().ListReleaseAssets(int64(1)).Return(nil, nil)
+				mockGHActionIface.EXPECT().UploadReleaseAsset(int64(1), "app.tar.gz", gomock.Any()).Return(nil, unprocessable)
+			},
+			expectedError: "uploading asset app.tar.gz: " + unprocessable.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+			tt.setupMock(mockGHActionIface)
+
+			err := executeAssets(mockGHActionIface, actionConfig, release)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("Missing files", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := actionConfig
+		config.Assets = []string{"build/*"}
+		assert.ErrorIs(t, executeAssets(utils.NewMockGithubActionIface(ctrl), config, release), ErrNoAssetsMatched)
+	})
+}
+
+func TestIsTransient(t *testing.T) {
+	response := func(status int) *github.ErrorResponse {
+		return &github.ErrorResponse{Response: &http.Response{StatusCode: status}}
+	}
+	assert.True(t, isTransient(response(http.StatusBadGateway)))
+	assert.True(t, isTransient(fmt.Errorf("listing assets: %w", response(http.StatusServiceUnavailable))))
+	assert.True(t, isTransient(&github.AbuseRateLimitError{}))
+	assert.True(t, isTransient(&url.Error{Op: "Post", URL: "https://uploads.github.com", Err: errors.New("connection reset")}))
+	assert.False(t, isTransient(response(http.StatusNotFound)))
+	assert.False(t, isTransient(response(http.StatusUnprocessableEntity)))
+	assert.False(t, isTransient(&os.PathError{Op: "open", Path: "app.tar.gz", Err: os.ErrNotExist}))
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/actions-go/toolkit/core"
+	"github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/notes"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+)
+
+const (
+	ChangelogNone        = "none"
+	ChangelogPush        = "push"
+	ChangelogPullRequest = "pull_request"
+)
+
+// ErrReleaseBranchMoved is returned when commits other than changelog
+// commits were pushed to the release branch after the release SHA. The
+// changelog can only be committed on top of the branch, so the tag would
+// contain them.
+var ErrReleaseBranchMoved = errors.New("release branch moved past the release SHA")
+
+// changelogCommitMessage is the message of the commit adding tag to file. It
+// is a Conventional Commit of type chore, so it does not release again.
+func changelogCommitMessage(file, tag string) string {
+	return fmt.Sprintf("chore(release): add %s to %s", tag, file)
+}
+
+// executeChangelog adds the section of the next tag to the changelog file.
+// With ChangelogPush it is committed to the release branch and the returned
+// config targets that commit, so the tag includes the changelog. When the
+// release branch moved past the release SHA, only changelog commits, e.g.
+// of an attempt that lost the tag race, may be in between; the changelog is
+// committed on top of them. Other commits fail with ErrReleaseBranchMoved,
+// they are released by the run of the commit that added them. With
+// ChangelogPullRequest a pull request is opened instead and the release
+// targets the release SHA.
+func executeChangelog(ghActionIface utils.GithubActionIface, actionConfig ActionConfig, data notes.Data) (ActionConfig, error) {
+	ref := actionConfig.CustomReleaseSHA
+	if actionConfig.Changelog == ChangelogPush {
+		head, err := ghActionIface.ResolveSHA(actionConfig.ReleaseBranch)
+		if err != nil {
+			return actionConfig, err
+		}
+		if head != actionConfig.CustomReleaseSHA {
+			commits, err := ghActionIface.ListCommits(actionConfig.CustomReleaseSHA, head)
+			if err != nil {
+				return actionConfig, err
+			}
+			// a previous run may have pushed the changelog before failing
+			if pushed := changelogCommit(commits, actionConfig.ChangelogFile, actionConfig.NextTag, actionConfig.CustomReleaseSHA); pushed != "" {
+				core.Infof("%s of %s was pushed already as %s", actionConfig.ChangelogFile, actionConfig.NextTag, pushed)
+				actionConfig.CustomReleaseSHA = pushed
+				return actionConfig, nil
+			}
+			if !onlyChangelogCommits(commits, actionConfig.ChangelogFile) {
+				return actionConfig, fmt.Errorf("%w: %s is at %s instead of %s, its changes are released by the run of that commit", ErrReleaseBranchMoved, actionConfig.ReleaseBranch, head, actionConfig.CustomReleaseSHA)
+			}
+			core.Warningf("%s moved from %s to %s by changelog commits, committing %s on top of them", actionConfig.ReleaseBranch, actionConfig.CustomReleaseSHA, head, actionConfig.ChangelogFile)
+			ref = head
+		}
+	}
+
+	changelog, blobSHA, err := ghActionIface.GetFileContent(actionConfig.ChangelogFile, ref)
+	if err != nil && !errors.Is(err, utils.ErrFileNotFound) {
+		return actionConfig, err
+	}
+	if notes.HasChangelogSection(changelog, actionConfig.NextTag) {
+		core.Infof("%s already has a section for %s, skipping changelog update", actionConfig.ChangelogFile, actionConfig.NextTag)
+		return actionConfig, nil
+	}
+	section, err := notes.Render(notes.ChangelogTemplate, data)
+	if err != nil {
+		return actionConfig, err
+	}
+	content := notes.PrependChangelogSection(changelog, section)
+	message := changelogCommitMessage(actionConfig.ChangelogFile, actionConfig.NextTag)
+
+	if actionConfig.Changelog == ChangelogPullRequest {
+		return actionConfig, openChangelogPullRequest(ghActionIface, actionConfig, content, message, blobSHA)
+	}
+
+	core.Debug("Committing changelog now")
+	sha, err := ghActionIface.CommitFile(actionConfig.ReleaseBranch, actionConfig.ChangelogFile, content, message, blobSHA)
+	if err != nil {
+		return actionConfig, err
+	}
+	core.Infof("Committed %s to %s as %s", actionConfig.ChangelogFile, actionConfig.ReleaseBranch, sha)
+	actionConfig.CustomReleaseSHA = sha
+	return actionConfig, nil
+}
+
+// openChangelogPullRequest commits the changelog to a new branch and opens a
+// pull request of it into the release branch. The branch and pull request
+// of a previous run are reused.
+func openChangelogPullRequest(ghActionIface utils.GithubActionIface, actionConfig ActionConfig, content, message, blobSHA string) error {
+	branch := "semver-sugar/changelog-" + actionConfig.NextTag
+	exists, err := ghActionIface.BranchExists(branch)
+	if err != nil {
+		return err
+	}
+	commit := true
+	if !exists {
+		err = ghActionIface.CreateBranch(branch, actionConfig.CustomReleaseSHA)
+	} else {
+		core.Infof("Branch %s exists already, updating it", branch)
+		var changelog string
+		changelog, blobSHA, err = ghActionIface.GetFileContent(actionConfig.ChangelogFile, branch)
+		if errors.Is(err, utils.ErrFileNotFound) {
+			err = nil
+		}
+		commit = !notes.HasChangelogSection(changelog, actionConfig.NextTag)
+	}
+	if err != nil {
+		return err
+	}
+	if commit {
+		if _, err := ghActionIface.CommitFile(branch, actionConfig.ChangelogFile, content, message, blobSHA); err != nil {
+			return err
+		}
+	}
+
+	url, err := ghActionIface.GetOpenPullRequest(branch, actionConfig.ReleaseBranch)
+	if err != nil {
+		return err
+	}
+	if url == "" {
+		body := fmt.Sprintf("Adds the changes of %s to %s.", actionConfig.NextTag, actionConfig.ChangelogFile)
+		url, err = ghActionIface.CreatePullRequest(branch, actionConfig.ReleaseBranch, message, body)
+		if err != nil {
+			return err
+		}
+		core.Infof("Opened pull request %s", url)
+	} else {
+		core.Infof("Pull request %s is open already", url)
+	}
+	core.SetOutput("changelog_pull_request", url)
+	return nil
+}
+
+// changelogCommit returns the SHA of the changelog commit of tag if it is
+// the first of commits and made directly on top of base, or an empty string.
+func changelogCommit(commits []*github.RepositoryCommit, file, tag, base string) string {
+	if len(commits) == 0 {
+		return ""
+	}
+	first := commits[0]
+	if len(first.Parents) == 0 || first.Parents[0].GetSHA() != base {
+		return ""
+	}
+	if first.GetCommit().GetMessage() != changelogCommitMessage(file, tag) {
+		return ""
+	}
+	return first.GetSHA()
+}
+
+// onlyChangelogCommits reports whether all commits add a section to file.
+func onlyChangelogCommits(commits []*github.RepositoryCommit, file string) bool {
+	for _, commit := range commits {
+		message := commit.GetCommit().GetMessage()
+		if !strings.HasPrefix(message, "chore(release): add ") || !strings.HasSuffix(message, " to "+file) {
+			return false
+		}
+	}
+	return true
+}
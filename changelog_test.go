@@ -0,0 +1,232 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	github "github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/notes"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteChangelog(t *testing.T) {
+	actionConfig := ActionConfig{
+		ReleaseBranch:    "main",
+		CurrentTag:       "v1.0.0",
+		NextTag:          "v1.1.0",
+		CustomReleaseSHA: "abc123",
+		ChangelogFile:    "CHANGELOG.md",
+	}
+	data := notes.NewData("v1.0.0", "v1.1.0", "minor", []notes.Commit{
+		{SHA: "aaa1111", Message: "feat: add flag", Author: "alice"},
+	}, nil, notes.DefaultChangelogSections)
+	data.Date = time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	changelog := "# Changelog\n\n## [v1.0.0] - 2024-09-01\n"
+	expected := "# Changelog\n\n## [v1.1.0] - 2024-10-01\n\n### Added\n\n- feat: add flag (aaa1111)\n\n## [v1.0.0] - 2024-09-01\n"
+	message := "chore(release): add v1.1.0 to CHANGELOG.md"
+	pushedCommit := &github.RepositoryCommit{
+		SHA:     github.String("def456"),
+		Parents: []*github.Commit{{SHA: github.String("abc123")}},
+		Commit:  &github.Commit{Message: github.String(message)},
+	}
+	featureCommit := &github.RepositoryCommit{
+		SHA:     github.String("fff999"),
+		Parents: []*github.Commit{{SHA: github.String("abc123")}},
+		Commit:  &github.Commit{Message: github.String("feat: another feature")},
+	}
+
+	tests := []struct {
+		name        string
+		changelog   string
+		setupMock   func(mockGHActionIface *utils.MockGithubActionIface)
+		expectedSHA string
+		expectedErr error
+	}{
+		{
+			name:      "Push",
+			changelog: ChangelogPush,
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().GetFileContent("CHANGELOG.md", "abc123").Return(changelog, "blob1", nil)
+				mockGHActionIface.EXPECT().ResolveSHA("main").Return("abc123", nil)
+				mockGHActionIface.EXPECT().CommitFile("main", "CHANGELOG.md", expected, message, "blob1").Return("def456", nil)
+			},
+			expectedSHA: "def456",
+		},
+		{
+			name:      "Push new changelog",
+			changelog: ChangelogPush,
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().GetFileContent("CHANGELOG.md", "abc123").Return("", "", utils.ErrFileNotFound)
+				mockGHActionIface.EXPECT().ResolveSHA("main").Return("abc123", nil)
+				mockGHActionIface.EXPECT().CommitFile("main", "CHANGELOG.md", gomock.Any(), message, "").Return("def456", nil)
+			},
+			expectedSHA: "def456",
+		},
+		{
+			name:      "Section exists",
+			changelog: ChangelogPush,
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().ResolveSHA("main").Return("abc123", nil)
+				mockGHActionIface.EXPECT().GetFileContent("CHANGELOG.md", "abc123").Return(expected, "blob2", nil)
+			},
+			expectedSHA: "abc123",
+		},
+		{
+			name:      "Pushed by a previous run",
+			changelog: ChangelogPush,
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().ResolveSHA("main").Return("def456", nil)
+				mockGHActionIface.EXPECT().ListCommits("abc123", "def456").Return([]*github.RepositoryCommit{pushedCommit}, nil)
+			},
+			expectedSHA: "def456",
+		},
+		{
+			name:      "Pushed by a previous run before a merge",
+			changelog: ChangelogPush,
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().ResolveSHA("main").Return("eee888", nil)
+				mockGHActionIface.EXPECT().ListCommits("abc123", "eee888").Return([]*github.RepositoryCommit{pushedCommit, {
+					SHA:     github.String("eee888"),
+					Parents: []*github.Commit{{SHA: github.String("def456")}},
+					Commit:  &github.Commit{Message: github.String("feat: another feature")},
+				}}, nil)
+			},
+			expectedSHA: "def456",
+		},
+		{
+			name:      "Release branch moved",
+			changelog: ChangelogPush,
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().ResolveSHA("main").Return("fff999", nil)
+				mockGHActionIface.EXPECT().ListCommits("abc123", "fff999").Return([]*github.RepositoryCommit{featureCommit}, nil)
+			},
+			expectedSHA: "abc123",
+			expectedErr: ErrReleaseBranchMoved,
+		},
+		{
+			name:      "Changelog of a lost tag race pushed",
+			changelog: ChangelogPush,
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().ResolveSHA("main").Return("fff999", nil)
+				mockGHActionIface.EXPECT().ListCommits("abc123", "fff999").Return([]*github.RepositoryCommit{{
+					SHA:     github.String("fff999"),
+					Parents: []*github.Commit{{SHA: github.String("abc123")}},
+					Commit:  &github.Commit{Message: github.String("chore(release): add v1.0.1 to CHANGELOG.md")},
+				}}, nil)
+				mockGHActionIface.EXPECT().GetFileContent("CHANGELOG.md", "fff999").Return("# Changelog\n\n## [v1.0.1] - 2024-09-30\n", "blob3", nil)
+				mockGHActionIface.EXPECT().CommitFile("main", "CHANGELOG.md", gomock.Any(), message, "blob3").Return("def456", nil)
+			},
+			expectedSHA: "def456",
+		},
+		{
+			name:      "Pull request",
+			changelog: ChangelogPullRequest,
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().GetFileContent("CHANGELOG.md", "abc123").Return(changelog, "blob1", nil)
+				mockGHActionIface.EXPECT().BranchExists("semver-sugar/changelog-v1.1.0").Return(false, nil)
+				mockGHActionIface.EXPECT().CreateBranch("semver-sugar/changelog-v1.1.0", "abc123").Return(nil)
+				mockGHActionIface.EXPECT().CommitFile("semver-sugar/changelog-v1.1.0", "CHANGELOG.md", expected, message, "blob1").Return("def456", nil)
+				mockGHActionIface.EXPECT().GetOpenPullRequest("semver-sugar/changelog-v1.1.0", "main").Return("", nil)
+				mockGHActionIface.EXPECT().CreatePullRequest("semver-sugar/changelog-v1.1.0", "main", message, gomock.Any()).Return("https://github.com/o/r/pull/8", nil)
+			},
+			expectedSHA: "abc123",
+		},
+		{
+			name:      "Pull request of a previous run",
+			changelog: ChangelogPullRequest,
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().GetFileContent("CHANGELOG.md", "abc123").Return(changelog, "blob1", nil)
+				mockGHActionIface.EXPECT().BranchExists("semver-sugar/changelog-v1.1.0").Return(true, nil)
+				mockGHActionIface.EXPECT().GetFileContent("CHANGELOG.md", "semver-sugar/changelog-v1.1.0").Return(expected, "blob2", nil)
+				mockGHActionIface.EXPECT().GetOpenPullRequest("semver-sugar/changelog-v1.1.0", "main").Return("https://github.com/o/r/pull/8", nil)
+			},
+			expectedSHA: "abc123",
+		},
+		{
+			name:      "Branch of a failed run",
+			changelog: ChangelogPullRequest,
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().GetFileContent("CHANGELOG.md", "abc123").Return(changelog, "blob1", nil)
+				mockGHActionIface.EXPECT().BranchExists("semver-sugar/changelog-v1.1.0").Return(true, nil)
+				mockGHActionIface.EXPECT().GetFileContent("CHANGELOG.md", "semver-sugar/changelog-v1.1.0").Return(changelog, "blob1", nil)
+				mockGHActionIface.EXPECT().CommitFile("semver-sugar/changelog-v1.1.0", "CHANGELOG.md", expected, message, "blob1").Return("def456", nil)
+				mockGHActionIface.EXPECT().GetOpenPullRequest("semver-sugar/changelog-v1.1.0", "main").Return("", nil)
+				mockGHActionIface.EXPECT().CreatePullRequest("semver-sugar/changelog-v1.1.0", "main", message, gomock.Any()).Return("https://github.com/o/r/pull/8", nil)
+			},
+			expectedSHA: "abc123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+			tt.setupMock(mockGHActionIface)
+
+			config := actionConfig
+			config.Changelog = tt.changelog
+			updated, err := executeChangelog(mockGHActionIface, config, data)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedSHA, updated.CustomReleaseSHA)
+		})
+	}
+}
+
+func TestExecuteAlreadyReleasedChangelogCommit(t *testing.T) {
+	actionConfig := ActionConfig{
+		TagFormat:        "v%major%.%minor%.%patch%",
+		VersionRange:     ">0.0.0",
+		CurrentTag:       "v1.1.0",
+		CustomReleaseSHA: "abc123",
+		Changelog:        ChangelogPush,
+		ChangelogFile:    "CHANGELOG.md",
+	}
+	changelogCommit := &github.RepositoryCommit{
+		SHA:     github.String("def456"),
+		Parents: []*github.Commit{{SHA: github.String("abc123")}},
+		Commit:  &github.Commit{Message: github.String("chore(release): add v1.1.0 to CHANGELOG.md")},
+	}
+
+	t.Run("Changelog commit of the release SHA", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+		mockGHActionIface.EXPECT().GetTagSHA("v1.1.0").Return("def456", nil)
+		mockGHActionIface.EXPECT().ListCommits("abc123", "def456").Return([]*github.RepositoryCommit{changelogCommit}, nil)
+		mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0 <1.1.0", "v%major%.%minor%.%patch%", "").Return("v1.0.0", nil)
+
+		released, alreadyReleased, err := executeAlreadyReleased(mockGHActionIface, actionConfig)
+		require.NoError(t, err)
+		assert.True(t, alreadyReleased)
+		assert.Equal(t, "def456", released.CustomReleaseSHA)
+		assert.Equal(t, "v1.0.0", released.CurrentTag)
+		assert.Equal(t, "v1.1.0", released.NextTag)
+	})
+
+	t.Run("Changelog commit of an earlier commit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// fff999 was merged after abc123 and is not part of v1.1.0
+		mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+		mockGHActionIface.EXPECT().GetTagSHA("v1.1.0").Return("def456", nil)
+		mockGHActionIface.EXPECT().ListCommits("fff999", "def456").Return([]*github.RepositoryCommit{changelogCommit}, nil)
+
+		config := actionConfig
+		config.CustomReleaseSHA = "fff999"
+		released, alreadyReleased, err := executeAlreadyReleased(mockGHActionIface, config)
+		require.NoError(t, err)
+		assert.False(t, alreadyReleased)
+		assert.Equal(t, config, released)
+	})
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/actions-go/toolkit/core"
+	version "github.com/blang/semver/v4"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+)
+
+const cliUsage = `Usage: semver-sugar <command> [flags] [args]
+
+Commands:
+  current            Print the latest tag of the repository
+  next               Print the tag the next release would get
+  bump <version>     Bump a version without looking at any repository
+  release            Create the next tag or release
+  validate <tag>     Check that a tag matches the tag format and version range
+
+Run "semver-sugar <command> -h" to list the flags of a command.
+`
+
+var ErrMissingArgument = errors.New("missing argument")
+
+type cliCommand func(args []string, stdout, stderr io.Writer) error
+
+var cliCommands = map[string]cliCommand{
+	"current":  runCurrent,
+	"next":     runNext,
+	"bump":     runBump,
+	"release":  runRelease,
+	"validate": runValidate,
+}
+
+// runCLI runs semver-sugar as a command line tool and returns the exit code.
+// Results go to stdout, errors and logs to stderr.
+func runCLI(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(stderr, cliUsage)
+		return 2
+	}
+	command, ok := cliCommands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command: %s\n\n%s", args[0], cliUsage)
+		return 2
+	}
+
+	err := command(args[1:], stdout, stderr)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case err != nil:
+		fmt.Fprintf(stderr, "error: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+// cliFlags maps the flags shared by all commands onto actionConfig.
+func cliFlags(name string, actionConfig *ActionConfig, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet("semver-sugar "+name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&actionConfig.TagFormat, "tag-format", semver.DefaultTagFormat, "format used to create and read tags")
+	flags.StringVar(&actionConfig.VersionRange, "version-range", ">0.0.0", "version range to use for the latest tag")
+	flags.StringVar(&actionConfig.Prerelease, "prerelease", "", "pre-release identifier (e.g. rc) to cut pre-release versions")
+	flags.StringVar(&actionConfig.Increment, "increment", "", "increment (patch, minor or major), Conventional Commits are used when empty")
+	return flags
+}
+
+// repositoryFlags maps the flags of commands talking to a repository onto
+// actionConfig. Defaults come from the GitHub Actions environment variables.
+func repositoryFlags(flags *flag.FlagSet, actionConfig *ActionConfig) {
+	flags.StringVar(&actionConfig.GithubRepository, "repo", os.Getenv("GITHUB_REPOSITORY"), "repository as owner/repo")
+	flags.StringVar(&actionConfig.GithubToken, "token", os.Getenv("GITHUB_TOKEN"), "GitHub token")
+	flags.StringVar(&actionConfig.GithubApiUrl, "api-url", "", "URL to GitHub Enterprise API")
+	flags.StringVar(&actionConfig.GithubUploadsUrl, "uploads-url", "", "URL to GitHub Enterprise uploads")
+	flags.StringVar(&actionConfig.CustomReleaseSHA, "sha", os.Getenv("GITHUB_SHA"), "commit to release, HEAD for the git backend when empty")
+	flags.StringVar(&actionConfig.Backend, "backend", BackendGithub, "backend to talk to the repository through (github or git)")
+	flags.StringVar(&actionConfig.GitDir, "git-dir", ".", "checkout used by the git backend")
+	flags.StringVar(&actionConfig.GitRemote, "git-remote", "", "remote the git backend pushes tags to, tags stay local when empty or none")
+	flags.StringVar(&actionConfig.TagType, "tag-type", TagTypeLightweight, "type of created tags (lightweight, annotated or signed)")
+	flags.StringVar(&actionConfig.TagMessage, "tag-message", "", "Go template of the message of annotated tags")
+	flags.StringVar(&actionConfig.TaggerName, "tagger-name", "", "name of the tagger of annotated tags")
+	flags.StringVar(&actionConfig.TaggerEmail, "tagger-email", "", "email of the tagger of annotated tags")
+	flags.StringVar(&actionConfig.SigningFormat, "signing-format", utils.SigningFormatGPG, "format of the signatures of signed tags (gpg or ssh)")
+	flags.StringVar(&actionConfig.SigningKey, "signing-key", "", "key ID or path of the key signed tags are signed with, git backend only")
+	flags.BoolVar(&actionConfig.ReachableTagsOnly, "reachable-only", false, "only consider tags reachable from -sha as the latest tag")
+	flags.BoolFunc("verbose", "print debug logs to stderr", func(string) error {
+		core.SetStdout(flags.Output())
+		return nil
+	})
+}
+
+func parseCLIFlags(flags *flag.FlagSet, args []string) error {
+	// logs of the action code stay out of the results unless asked for
+	core.SetStdout(io.Discard)
+	return flags.Parse(args)
+}
+
+func runCurrent(args []string, stdout, stderr io.Writer) error {
+	var actionConfig ActionConfig
+	flags := cliFlags("current", &actionConfig, stderr)
+	repositoryFlags(flags, &actionConfig)
+	if err := parseCLIFlags(flags, args); err != nil {
+		return err
+	}
+
+	ghActionIface, err := newBackend(actionConfig)
+	if err != nil {
+		return err
+	}
+	if actionConfig.ReachableTagsOnly {
+		actionConfig, err = resolveCLISHA(ghActionIface, actionConfig)
+		if err != nil {
+			return err
+		}
+	}
+	latestTag, err := ghActionIface.GetGithubLatestTag(actionConfig.VersionRange, actionConfig.TagFormat, latestTagReachableFrom(actionConfig))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, latestTag)
+	return nil
+}
+
+func runNext(args []string, stdout, stderr io.Writer) error {
+	var actionConfig ActionConfig
+	flags := cliFlags("next", &actionConfig, stderr)
+	repositoryFlags(flags, &actionConfig)
+	if err := parseCLIFlags(flags, args); err != nil {
+		return err
+	}
+
+	actionConfig, err := cliNextTag(actionConfig)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, actionConfig.NextTag)
+	return nil
+}
+
+func runRelease(args []string, stdout, stderr io.Writer) error {
+	var actionConfig ActionConfig
+	flags := cliFlags("release", &actionConfig, stderr)
+	repositoryFlags(flags, &actionConfig)
+	flags.StringVar(&actionConfig.ReleaseStrategy, "strategy", ReleaseStrategyRelease, "release strategy (release, tag or none)")
+	flags.StringVar(&actionConfig.NextTag, "tag", "", "tag to create instead of the next one")
+	flags.BoolVar(&actionConfig.DryRun, "dry-run", false, "print the release plan as JSON instead of releasing")
+	flags.IntVar(&actionConfig.MaxRetries, "max-retries", 3, "how often to compute the next tag again when a concurrent release created it first")
+	flags.BoolVar(&actionConfig.Draft, "draft", false, "create the release as a draft")
+	flags.StringVar(&actionConfig.MarkPrerelease, "mark-prerelease", MarkPrereleaseAuto, "mark the release as a pre-release (auto, true or false)")
+	flags.StringVar(&actionConfig.MakeLatest, "make-latest", "", "make the release the latest release (true, false or legacy)")
+	flags.Func("floating-tags", "comma separated formats of tags moved to the release, e.g. v%major%,v%major%.%minor%", func(value string) error {
+		actionConfig.FloatingTags = ParseFloatingTags(value)
+		return nil
+	})
+	flags.Func("asset", "glob pattern of files uploaded to the release, may be repeated", func(value string) error {
+		actionConfig.Assets = append(actionConfig.Assets, value)
+		return nil
+	})
+	flags.BoolVar(&actionConfig.AssetChecksums, "asset-checksums", true, "upload a "+ChecksumsFile+" file with the checksums of the assets")
+	if err := parseCLIFlags(flags, args); err != nil {
+		return err
+	}
+
+	if actionConfig.CustomReleaseSHA == "" && actionConfig.Backend != BackendGit {
+		return fmt.Errorf("%w: -sha", ErrMissingArgument)
+	}
+	if err := errors.Join(
+		validateChoice("-strategy", actionConfig.ReleaseStrategy, ReleaseStrategyRelease, ReleaseStrategyTag, ReleaseStrategyNone),
+		validateBackend(actionConfig),
+		validateTagger(actionConfig),
+	); err != nil {
+		return err
+	}
+
+	ghActionIface, actionConfig, err := cliBackend(actionConfig)
+	if err != nil {
+		return err
+	}
+	actionConfig, _, err = executeRelease(ghActionIface, actionConfig, manualIncrement{incr: actionConfig.Increment}, false)
+	if err != nil {
+		return err
+	}
+	if actionConfig.DryRun {
+		plan, err := newReleasePlan(actionConfig, false)
+		if err != nil {
+			return err
+		}
+		planJSON, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, string(planJSON))
+		return nil
+	}
+	fmt.Fprintln(stdout, actionConfig.NextTag)
+	return nil
+}
+
+// cliNextTag computes the next tag from the -increment flag or, without it,
+// from the Conventional Commits since the latest tag.
+func cliNextTag(actionConfig ActionConfig) (ActionConfig, error) {
+	ghActionIface, actionConfig, err := cliBackend(actionConfig)
+	if err != nil {
+		return actionConfig, err
+	}
+	return executeNextTag(ghActionIface, actionConfig, manualIncrement{incr: actionConfig.Increment})
+}
+
+// cliBackend creates the backend and resolves the release SHA. The increment
+// comes from the -increment flag or, without it, from the commits.
+func cliBackend(actionConfig ActionConfig) (utils.GithubActionIface, ActionConfig, error) {
+	ghActionIface, err := newBackend(actionConfig)
+	if err != nil {
+		return nil, actionConfig, err
+	}
+	actionConfig, err = resolveCLISHA(ghActionIface, actionConfig)
+	if err != nil {
+		return nil, actionConfig, err
+	}
+	actionConfig.IncrementSource = IncrementSourceLabels
+	if actionConfig.Increment == "" {
+		actionConfig.IncrementSource = IncrementSourceCommits
+	}
+	return ghActionIface, actionConfig, nil
+}
+
+// resolveCLISHA resolves the -sha flag, which also takes branches, tags and
+// other refs, to a commit SHA. The git backend defaults to HEAD.
+func resolveCLISHA(ghActionIface utils.GithubActionIface, actionConfig ActionConfig) (ActionConfig, error) {
+	if actionConfig.CustomReleaseSHA == "" && actionConfig.Backend == BackendGit {
+		actionConfig.CustomReleaseSHA = "HEAD"
+	}
+	if actionConfig.CustomReleaseSHA == "" {
+		return actionConfig, nil
+	}
+	sha, err := ghActionIface.ResolveSHA(actionConfig.CustomReleaseSHA)
+	actionConfig.CustomReleaseSHA = sha
+	return actionConfig, err
+}
+
+func runBump(args []string, stdout, stderr io.Writer) error {
+	var actionConfig ActionConfig
+	flags := cliFlags("bump", &actionConfig, stderr)
+	if err := parseCLIFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("%w: version to bump", ErrMissingArgument)
+	}
+	if actionConfig.Increment == "" {
+		actionConfig.Increment = string(semver.IncrementPatch)
+	}
+
+	var nextTag string
+	var err error
+	if actionConfig.Prerelease != "" {
+		nextTag, err = semver.BumpSemverPrerelease(flags.Arg(0), actionConfig.Increment, actionConfig.Prerelease, actionConfig.TagFormat)
+	} else {
+		nextTag, err = semver.BumpSemverVersion(flags.Arg(0), actionConfig.Increment, actionConfig.TagFormat)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, nextTag)
+	return nil
+}
+
+func runValidate(args []string, stdout, stderr io.Writer) error {
+	var actionConfig ActionConfig
+	flags := cliFlags("validate", &actionConfig, stderr)
+	if err := parseCLIFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("%w: tag to validate", ErrMissingArgument)
+	}
+
+	v, err := semver.ParseTag(actionConfig.TagFormat, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	parsed := strings.TrimPrefix(v.String(), "v")
+	expectedRange, err := version.ParseRange(actionConfig.VersionRange)
+	if err != nil {
+		return err
+	}
+	if !expectedRange(version.MustParse(parsed)) {
+		return fmt.Errorf("version %s is not in range %q", parsed, actionConfig.VersionRange)
+	}
+	fmt.Fprintln(stdout, parsed)
+	return nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	github "github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCLIOffline(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedCode   int
+		expectedStdout string
+	}{
+		{
+			name:           "bump",
+			args:           []string{"bump", "-increment", "minor", "v1.2.3"},
+			expectedCode:   0,
+			expectedStdout: "v1.3.0\n",
+		},
+		{
+			name:           "bump pre-release with custom format",
+			args:           []string{"bump", "-increment", "minor", "-prerelease", "rc", "-tag-format", "api/v%major%.%minor%.%patch%", "api/v1.2.3"},
+			expectedCode:   0,
+			expectedStdout: "api/v1.3.0-rc.1\n",
+		},
+		{
+			name:         "bump without version",
+			args:         []string{"bump"},
+			expectedCode: 1,
+		},
+		{
+			name:           "validate",
+			args:           []string{"validate", "-tag-format", "release-%major%.%minor%.%patch%", "release-1.2.3"},
+			expectedCode:   0,
+			expectedStdout: "1.2.3\n",
+		},
+		{
+			name:         "validate tag with other format",
+			args:         []string{"validate", "release-1.2.3"},
+			expectedCode: 1,
+		},
+		{
+			name:         "validate version out of range",
+			args:         []string{"validate", "-version-range", "<1.0.0", "v1.2.3"},
+			expectedCode: 1,
+		},
+		{
+			name:         "unknown command",
+			args:         []string{"publish"},
+			expectedCode: 2,
+		},
+		{
+			name:         "no command",
+			args:         []string{},
+			expectedCode: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := runCLI(tt.args, &stdout, &stderr)
+			assert.Equal(t, tt.expectedCode, code, stderr.String())
+			assert.Equal(t, tt.expectedStdout, stdout.String())
+		})
+	}
+}
+
+func TestRunCLIWithRepository(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+	defaultBackend := newBackend
+	newBackend = func(actionConfig ActionConfig) (utils.GithubActionIface, error) {
+		return mockGHActionIface, nil
+	}
+	defer func() { newBackend = defaultBackend }()
+
+	tests := []struct {
+		name           string
+		args           []string
+		setupMock      func()
+		expectedCode   int
+		expectedStdout string
+	}{
+		{
+			name: "current",
+			args: []string{"current", "-version-range", ">=1.0.0 <2.0.0"},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">=1.0.0 <2.0.0", "v%major%.%minor%.%patch%", "").Return("v1.4.2", nil)
+			},
+			expectedCode:   0,
+			expectedStdout: "v1.4.2\n",
+		},
+		{
+			name: "next with increment",
+			args: []string{"next", "-increment", "major"},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", "v%major%.%minor%.%patch%", "").Return("v1.4.2", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.4.2").Return("def456", nil)
+				mockGHActionIface.EXPECT().GetNextTag("v1.4.2", "major", "v%major%.%minor%.%patch%", "").Return("v2.0.0", nil)
+			},
+			expectedCode:   0,
+			expectedStdout: "v2.0.0\n",
+		},
+		{
+			name: "next from commits",
+			args: []string{"next", "-sha", "abc123"},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ResolveSHA("abc123").Return("abc123", nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", "v%major%.%minor%.%patch%", "").Return("v1.4.2", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.4.2").Return("def456", nil)
+				mockGHActionIface.EXPECT().ListCommits("v1.4.2", "abc123").Return([]*github.RepositoryCommit{
+					{Commit: &github.Commit{Message: github.String("feat: add cli")}},
+				}, nil)
+				mockGHActionIface.EXPECT().GetNextTag("v1.4.2", "minor", "v%major%.%minor%.%patch%", "").Return("v1.5.0", nil)
+			},
+			expectedCode:   0,
+			expectedStdout: "v1.5.0\n",
+		},
+		{
+			name: "release",
+			args: []string{"release", "-increment", "patch", "-strategy", "tag", "-sha", "main"},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ResolveSHA("main").Return("abc123", nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", "v%major%.%minor%.%patch%", "").Return("v1.4.2", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.4.2").Return("def456", nil)
+				mockGHActionIface.EXPECT().GetNextTag("v1.4.2", "patch", "v%major%.%minor%.%patch%", "").Return("v1.4.3", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.4.3").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubTag("v1.4.3", "abc123", utils.TagOptions{}).Return(nil)
+			},
+			expectedCode:   0,
+			expectedStdout: "v1.4.3\n",
+		},
+		{
+			name: "release dry run",
+			args: []string{"release", "-increment", "patch", "-strategy", "tag", "-sha", "abc123", "-dry-run"},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ResolveSHA("abc123").Return("abc123", nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", "v%major%.%minor%.%patch%", "").Return("v1.4.2", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.4.2").Return("def456", nil)
+				mockGHActionIface.EXPECT().GetNextTag("v1.4.2", "patch", "v%major%.%minor%.%patch%", "").Return("v1.4.3", nil)
+			},
+			expectedCode: 0,
+			expectedStdout: `{
+  "strategy": "tag",
+  "previous_tag": "v1.4.2",
+  "tag": "v1.4.3",
+  "increment": "patch",
+  "target_sha": "abc123",
+  "skipped": false,
+  "steps": [
+    "create tag v1.4.3 at abc123"
+  ]
+}
+`,
+		},
+		{
+			name:         "release with invalid strategy",
+			args:         []string{"release", "-increment", "patch", "-strategy", "relase", "-sha", "abc123", "-dry-run"},
+			setupMock:    func() {},
+			expectedCode: 1,
+		},
+		{
+			name:         "release without sha",
+			args:         []string{"release", "-increment", "patch", "-sha", ""},
+			setupMock:    func() {},
+			expectedCode: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+			var stdout, stderr bytes.Buffer
+			code := runCLI(tt.args, &stdout, &stderr)
+			assert.Equal(t, tt.expectedCode, code, stderr.String())
+			assert.Equal(t, tt.expectedStdout, stdout.String())
+		})
+	}
+}
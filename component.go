@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/actions-go/toolkit/core"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// BootstrapVersion is bumped for the first release of a component without
+// a tag matching its tag format.
+const BootstrapVersion = "0.0.0"
+
+var ErrInvalidComponent = errors.New("invalid component")
+
+// Component is an independently versioned part of a monorepo, e.g. a Go
+// module in a subdirectory. It is released when files below Path changed
+// since its latest tag.
+type Component struct {
+	Name         string `yaml:"name" json:"name"`
+	Path         string `yaml:"path" json:"path"`
+	TagFormat    string `yaml:"tag_format" json:"tag_format"`
+	VersionRange string `yaml:"version_range" json:"version_range"`
+}
+
+// componentRelease is the result of releasing one component.
+type componentRelease struct {
+	Name        string `json:"name"`
+	PreviousTag string `json:"previous_tag"`
+	Tag         string `json:"tag"`
+	Increment   string `json:"increment"`
+}
+
+// ParseComponents parses the YAML list of components of the components
+// input.
+func ParseComponents(input string) ([]Component, error) {
+	var components []Component
+	if err := yaml.Unmarshal([]byte(input), &components); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidComponent, err)
+	}
+	return normalizeComponents(components)
+}
+
+// normalizeComponents checks components and fills in their defaults. The tag
+// format defaults to "<path>/v%major%.%minor%.%patch%".
+func normalizeComponents(components []Component) ([]Component, error) {
+	names := map[string]bool{}
+	for i := range components {
+		component := &components[i]
+		component.Path = strings.Trim(component.Path, "/")
+		if component.Name == "" || component.Path == "" {
+			return nil, fmt.Errorf("%w: component %d needs a name and a path", ErrInvalidComponent, i+1)
+		}
+		if names[component.Name] {
+			return nil, fmt.Errorf("%w: duplicate component %s", ErrInvalidComponent, component.Name)
+		}
+		names[component.Name] = true
+		if component.TagFormat == "" {
+			component.TagFormat = component.Path + "/" + semver.DefaultTagFormat
+		}
+	}
+	return components, nil
+}
+
+// contains reports whether file lies below the path of the component.
+func (c Component) contains(file string) bool {
+	return file == c.Path || strings.HasPrefix(file, c.Path+"/")
+}
+
+// forComponent returns actionConfig with the tag format and version range of
+// component.
+func (actionConfig ActionConfig) forComponent(component Component) ActionConfig {
+	actionConfig.TagFormat = component.TagFormat
+	actionConfig.ComponentPath = component.Path
+	if component.VersionRange != "" {
+		actionConfig.VersionRange = component.VersionRange
+	}
+	actionConfig.CurrentTag = ""
+	actionConfig.NextTag = ""
+	actionConfig.Increment = ""
+	return actionConfig
+}
+
+// isComponentChanged reports whether any file below the path of component
+// changed between its latest tag and the release SHA.
+func isComponentChanged(ghActionIface utils.GithubActionIface, component Component, actionConfig ActionConfig) (bool, error) {
+	files, err := ghActionIface.ListChangedFiles(actionConfig.CurrentTag, actionConfig.CustomReleaseSHA)
+	if err != nil {
+		return false, err
+	}
+	for _, file := range files {
+		if component.contains(file) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// executeComponentNextTag computes the next tag of component. The returned
+// bool is false when the component has nothing to release. A component
+// without a tag yet is released by bumping BootstrapVersion, one whose
+// latest tag already points to the release SHA completes that release like
+// executeAlreadyReleased.
+func executeComponentNextTag(ghActionIface utils.GithubActionIface, component Component, actionConfig ActionConfig, labels labelSource) (ActionConfig, bool, error) {
+	actionConfig, err := executeLatestTag(ghActionIface, actionConfig.forComponent(component))
+	switch {
+	case errors.Is(err, utils.ErrNoMatchingTag):
+		core.Infof("Component %s has no tag matching %s yet, bumping %s", component.Name, actionConfig.TagFormat, BootstrapVersion)
+		actionConfig.CurrentTag = ""
+	case err != nil:
+		return actionConfig, false, err
+	default:
+		var released bool
+		actionConfig, released, err = executeAlreadyReleased(ghActionIface, actionConfig)
+		if err != nil || released {
+			return actionConfig, released, err
+		}
+		changed, err := isComponentChanged(ghActionIface, component, actionConfig)
+		if err != nil || !changed {
+			return actionConfig, false, err
+		}
+	}
+	actionConfig, err = executeBump(ghActionIface, actionConfig, labels)
+	if errors.Is(err, semver.ErrNoReleasableCommits) {
+		return actionConfig, false, nil
+	}
+	return actionConfig, err == nil, err
+}
+
+// executeComponentRelease computes the next tag of component and, unless
+// this is a dry run or the release is skipped, creates its tag or release.
+// Like executeRelease, it starts over when a concurrent run created the tag
+// first.
+func executeComponentRelease(ghActionIface utils.GithubActionIface, component Component, actionConfig ActionConfig, labels labelSource, isSkipRelease bool) (ActionConfig, bool, error) {
+	var componentConfig ActionConfig
+	var release bool
+	err := retryOnTagExists(actionConfig, func() error {
+		var err error
+		componentConfig, release, err = executeComponentNextTag(ghActionIface, component, actionConfig, labels)
+		if err != nil || !release || actionConfig.DryRun || isSkipRelease {
+			return err
+		}
+		var options utils.ReleaseOptions
+		componentConfig, options, err = executeReleaseContent(ghActionIface, componentConfig)
+		if err != nil {
+			return err
+		}
+		_, err = executeCreateRelease(ghActionIface, componentConfig.CustomReleaseSHA, componentConfig.CurrentTag, componentConfig.NextTag, componentConfig.ReleaseStrategy, options)
+		return concurrentTagConflict(ghActionIface, componentConfig, err)
+	})
+	return componentConfig, release, err
+}
+
+// executeComponentReleases releases every component that changed since its
+// latest tag, each with its own tag format and version range.
+func executeComponentReleases(ghActionIface utils.GithubActionIface, actionConfig ActionConfig, labels labelSource, isSkipRelease bool) error {
+	ghActionIface = newPullRequestCache(ghActionIface)
+	releases := []componentRelease{}
+	plans := []releasePlan{}
+	for _, component := range actionConfig.Components {
+		componentConfig, release, err := executeComponentRelease(ghActionIface, component, actionConfig, labels, isSkipRelease)
+		if err != nil {
+			return fmt.Errorf("component %s: %w", component.Name, err)
+		}
+		if !release {
+			core.Infof("Component %s has nothing to release since %s", component.Name, componentConfig.CurrentTag)
+			continue
+		}
+
+		switch {
+		case actionConfig.DryRun:
+			plan, err := newReleasePlan(componentConfig, isSkipRelease)
+			if err != nil {
+				return fmt.Errorf("component %s: %w", component.Name, err)
+			}
+			plan.Component = component.Name
+			plans = append(plans, plan)
+			core.Infof("Dry run, component %s would be released: %s -> %s (%s)", component.Name, plan.PreviousTag, plan.Tag, plan.Increment)
+		case isSkipRelease:
+			core.Infof("Skipping release creation of component %s because of skip-release label", component.Name)
+		default:
+			core.Infof("Component %s: tag was: %v and next tag created was: %v, increment was: %v", component.Name, componentConfig.CurrentTag, componentConfig.NextTag, componentConfig.Increment)
+		}
+		releases = append(releases, componentRelease{
+			Name:        component.Name,
+			PreviousTag: componentConfig.CurrentTag,
+			Tag:         componentConfig.NextTag,
+			Increment:   componentConfig.Increment,
+		})
+	}
+
+	if actionConfig.DryRun {
+		return setJSONOutput("plan", plans)
+	}
+	return setJSONOutput("components", releases)
+}
+
+func setJSONOutput(name string, value any) error {
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	core.SetOutput(name, string(valueJSON))
+	return nil
+}
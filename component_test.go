@@ -0,0 +1,263 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	github "github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseComponents(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      []Component
+		expectedError error
+	}{
+		{
+			name:     "Empty input",
+			input:    "",
+			expected: nil,
+		},
+		{
+			name: "Components with defaults",
+			input: `
+- name: api
+  path: services/api/
+  version_range: ">=2.0.0"
+- name: auth
+  path: libs/auth
+  tag_format: "auth-%major%.%minor%.%patch%"
+`,
+			expected: []Component{
+				{Name: "api", Path: "services/api", TagFormat: "services/api/v%major%.%minor%.%patch%", VersionRange: ">=2.0.0"},
+				{Name: "auth", Path: "libs/auth", TagFormat: "auth-%major%.%minor%.%patch%"},
+			},
+		},
+		{
+			name:          "Missing path",
+			input:         "- name: api",
+			expectedError: ErrInvalidComponent,
+		},
+		{
+			name:          "Duplicate name",
+			input:         "[{name: api, path: a}, {name: api, path: b}]",
+			expectedError: ErrInvalidComponent,
+		},
+		{
+			name:          "Invalid YAML",
+			input:         "name: api",
+			expectedError: ErrInvalidComponent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components, err := ParseComponents(tt.input)
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expected, components)
+		})
+	}
+}
+
+func TestExecuteComponentReleases(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+
+	components := []Component{
+		{Name: "api", Path: "services/api", TagFormat: "services/api/v%major%.%minor%.%patch%"},
+		{Name: "auth", Path: "libs/auth", TagFormat: "libs/auth/v%major%.%minor%.%patch%", VersionRange: ">=1.0.0 <2.0.0"},
+	}
+	actionConfig := ActionConfig{
+		ReleaseStrategy:  ReleaseStrategyTag,
+		VersionRange:     ">0.0.0",
+		CustomReleaseSHA: "abc123",
+		EventName:        EventWorkflowDispatch,
+		Components:       components,
+	}
+
+	tests := []struct {
+		name          string
+		dryRun        bool
+		setupMock     func()
+		expectedError string
+	}{
+		{
+			name: "Only changed components are released",
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", "services/api/v%major%.%minor%.%patch%", "").Return("services/api/v2.3.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("services/api/v2.3.0").Return("aaa111", nil)
+				mockGHActionIface.EXPECT().ListChangedFiles("services/api/v2.3.0", "abc123").Return([]string{"services/api-docs/README.md", "libs/auth/token.go"}, nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">=1.0.0 <2.0.0", "libs/auth/v%major%.%minor%.%patch%", "").Return("libs/auth/v1.0.4", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("libs/auth/v1.0.4").Return("bbb222", nil)
+				mockGHActionIface.EXPECT().ListChangedFiles("libs/auth/v1.0.4", "abc123").Return([]string{"libs/auth/token.go"}, nil)
+				mockGHActionIface.EXPECT().GetNextTag("libs/auth/v1.0.4", "minor", "libs/auth/v%major%.%minor%.%patch%", "").Return("libs/auth/v1.1.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("libs/auth/v1.1.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubTag("libs/auth/v1.1.0", "abc123", utils.TagOptions{}).Return(nil)
+			},
+		},
+		{
+			name:   "Dry run creates nothing",
+			dryRun: true,
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", "services/api/v%major%.%minor%.%patch%", "").Return("services/api/v2.3.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("services/api/v2.3.0").Return("aaa111", nil)
+				mockGHActionIface.EXPECT().ListChangedFiles("services/api/v2.3.0", "abc123").Return([]string{"services/api/main.go"}, nil)
+				mockGHActionIface.EXPECT().GetNextTag("services/api/v2.3.0", "minor", "services/api/v%major%.%minor%.%patch%", "").Return("services/api/v2.4.0", nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">=1.0.0 <2.0.0", "libs/auth/v%major%.%minor%.%patch%", "").Return("libs/auth/v1.0.4", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("libs/auth/v1.0.4").Return("bbb222", nil)
+				mockGHActionIface.EXPECT().ListChangedFiles("libs/auth/v1.0.4", "abc123").Return(nil, nil)
+			},
+		},
+		{
+			name: "Error listing changed files",
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", "services/api/v%major%.%minor%.%patch%", "").Return("services/api/v2.3.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("services/api/v2.3.0").Return("aaa111", nil)
+				mockGHActionIface.EXPECT().ListChangedFiles("services/api/v2.3.0", "abc123").Return(nil, errors.New("compare failed"))
+			},
+			expectedError: "component api: compare failed",
+		},
+		{
+			name: "Re-run completes the release of a component",
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", "services/api/v%major%.%minor%.%patch%", "").Return("services/api/v2.3.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("services/api/v2.3.0").Return("aaa111", nil)
+				mockGHActionIface.EXPECT().ListChangedFiles("services/api/v2.3.0", "abc123").Return(nil, nil)
+				// the tag of auth was created by the failed run, nothing changed since
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">=1.0.0 <2.0.0", "libs/auth/v%major%.%minor%.%patch%", "").Return("libs/auth/v1.1.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("libs/auth/v1.1.0").Return("abc123", nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">=1.0.0 <2.0.0 <1.1.0", "libs/auth/v%major%.%minor%.%patch%", "").Return("libs/auth/v1.0.4", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("libs/auth/v1.1.0").Return("abc123", nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+			actionConfig.DryRun = tt.dryRun
+			err := executeComponentReleases(mockGHActionIface, actionConfig, manualIncrement{incr: "minor"}, false)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExecuteComponentNextTag(t *testing.T) {
+	component := Component{Name: "auth", Path: "libs/auth", TagFormat: "libs/auth/v%major%.%minor%.%patch%"}
+	actionConfig := ActionConfig{
+		VersionRange:     ">0.0.0",
+		CustomReleaseSHA: "abc123",
+		IncrementSource:  IncrementSourceCommits,
+	}
+	commit := func(message string) *github.RepositoryCommit {
+		return &github.RepositoryCommit{Commit: &github.Commit{Message: github.String(message)}}
+	}
+
+	tests := []struct {
+		name            string
+		setupMock       func(mockGHActionIface *utils.MockGithubActionIface)
+		expectedRelease bool
+		expectedCurrent string
+		expectedNext    string
+	}{
+		{
+			name: "Only commits below the path count",
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", component.TagFormat, "").Return("libs/auth/v1.0.4", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("libs/auth/v1.0.4").Return("bbb222", nil)
+				mockGHActionIface.EXPECT().ListChangedFiles("libs/auth/v1.0.4", "abc123").Return([]string{"libs/auth/token.go", "services/api/main.go"}, nil)
+				// the feat: commit of services/api is not listed
+				mockGHActionIface.EXPECT().ListPathCommits("libs/auth/v1.0.4", "abc123", "libs/auth").Return([]*github.RepositoryCommit{commit("fix: refresh tokens")}, nil)
+				mockGHActionIface.EXPECT().GetNextTag("libs/auth/v1.0.4", "patch", component.TagFormat, "").Return("libs/auth/v1.0.5", nil)
+			},
+			expectedRelease: true,
+			expectedCurrent: "libs/auth/v1.0.4",
+			expectedNext:    "libs/auth/v1.0.5",
+		},
+		{
+			name: "Changed without releasable commits",
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", component.TagFormat, "").Return("libs/auth/v1.0.4", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("libs/auth/v1.0.4").Return("bbb222", nil)
+				mockGHActionIface.EXPECT().ListChangedFiles("libs/auth/v1.0.4", "abc123").Return([]string{"libs/auth/README.md"}, nil)
+				mockGHActionIface.EXPECT().ListPathCommits("libs/auth/v1.0.4", "abc123", "libs/auth").Return([]*github.RepositoryCommit{commit("docs: explain tokens")}, nil)
+			},
+			expectedCurrent: "libs/auth/v1.0.4",
+		},
+		{
+			name: "First release of a component",
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", component.TagFormat, "").Return("", utils.ErrNoMatchingTag)
+				mockGHActionIface.EXPECT().ListPathCommits("", "abc123", "libs/auth").Return([]*github.RepositoryCommit{commit("feat: add auth")}, nil)
+				mockGHActionIface.EXPECT().GetNextTag(BootstrapVersion, "minor", component.TagFormat, "").Return("libs/auth/v0.1.0", nil)
+			},
+			expectedRelease: true,
+			expectedNext:    "libs/auth/v0.1.0",
+		},
+		{
+			name: "Tagged by a failed run",
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", component.TagFormat, "").Return("libs/auth/v1.0.5", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("libs/auth/v1.0.5").Return("abc123", nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0 <1.0.5", component.TagFormat, "").Return("libs/auth/v1.0.4", nil)
+			},
+			expectedRelease: true,
+			expectedCurrent: "libs/auth/v1.0.4",
+			expectedNext:    "libs/auth/v1.0.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+			tt.setupMock(mockGHActionIface)
+
+			componentConfig, release, err := executeComponentNextTag(mockGHActionIface, component, actionConfig, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRelease, release)
+			assert.Equal(t, tt.expectedCurrent, componentConfig.CurrentTag)
+			assert.Equal(t, tt.expectedNext, componentConfig.NextTag)
+		})
+	}
+}
+
+func TestExecuteComponentReleaseNotes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+	component := Component{Name: "auth", Path: "libs/auth", TagFormat: "libs/auth/v%major%.%minor%.%patch%"}
+	actionConfig := ActionConfig{
+		ReleaseStrategy:  ReleaseStrategyRelease,
+		VersionRange:     ">0.0.0",
+		CustomReleaseSHA: "abc123",
+	}
+	notes := "**Full Changelog**: libs/auth/v1.0.4...libs/auth/v1.1.0"
+
+	mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", component.TagFormat, "").Return("libs/auth/v1.0.4", nil)
+	mockGHActionIface.EXPECT().GetTagSHA("libs/auth/v1.0.4").Return("bbb222", nil)
+	mockGHActionIface.EXPECT().ListChangedFiles("libs/auth/v1.0.4", "abc123").Return([]string{"libs/auth/token.go"}, nil)
+	mockGHActionIface.EXPECT().GetNextTag("libs/auth/v1.0.4", "minor", component.TagFormat, "").Return("libs/auth/v1.1.0", nil)
+	mockGHActionIface.EXPECT().GetTagSHA("libs/auth/v1.1.0").Return("", utils.ErrTagNotFound)
+	// the notes start at the previous tag of the component, not at the
+	// latest release of any component
+	mockGHActionIface.EXPECT().GenerateReleaseNotes("libs/auth/v1.1.0", "abc123", "libs/auth/v1.0.4").Return(&github.RepositoryReleaseNotes{Body: notes}, nil, nil)
+	mockGHActionIface.EXPECT().CreateGithubRelease("libs/auth/v1.1.0", "abc123", utils.ReleaseOptions{Body: notes}).Return(&github.RepositoryRelease{ID: github.Int64(1)}, nil)
+
+	_, release, err := executeComponentRelease(mockGHActionIface, component, actionConfig, manualIncrement{incr: "minor"}, false)
+	assert.NoError(t, err)
+	assert.True(t, release)
+}
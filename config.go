@@ -0,0 +1,321 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	version "github.com/blang/semver/v4"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/notes"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ConfigVersion is the version of the config file format.
+	ConfigVersion = 1
+	// DefaultConfigFile is read from the workspace when present.
+	DefaultConfigFile = ".semver-sugar.yml"
+)
+
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// ConfigFile is the repository config file. Its fields are named after the
+// action inputs they provide defaults for, see schema/semver-sugar.schema.json.
+type ConfigFile struct {
+	Version           int                 `yaml:"version"`
+	ReleaseBranch     string              `yaml:"release_branch"`
+	ReleaseStrategy   string              `yaml:"release_strategy"`
+	TagFormat         string              `yaml:"tag_format"`
+	VersionRange      string              `yaml:"version_range"`
+	Prerelease        string              `yaml:"prerelease"`
+	IncrementSource   string              `yaml:"increment_source"`
+	ReachableTagsOnly *bool               `yaml:"reachable_tags_only"`
+	MaxRetries        *int                `yaml:"max_retries"`
+	LabelMapping      semver.LabelMapping `yaml:"label_mapping"`
+	LabelPolicy       string              `yaml:"label_policy"`
+	Components        []Component         `yaml:"components"`
+	Backend           string              `yaml:"backend"`
+	GitRemote         string              `yaml:"git_remote"`
+	TagType           string              `yaml:"tag_type"`
+	TagMessage        string              `yaml:"tag_message"`
+	TaggerName        string              `yaml:"tagger_name"`
+	TaggerEmail       string              `yaml:"tagger_email"`
+	SigningFormat     string              `yaml:"signing_format"`
+	Draft             *bool               `yaml:"draft"`
+	MarkPrerelease    string              `yaml:"mark_prerelease"`
+	MakeLatest        string              `yaml:"make_latest"`
+	ReleaseNotes      string              `yaml:"release_notes"`
+	NotesTemplate     string              `yaml:"release_notes_template"`
+	NotesSections     []notes.Section     `yaml:"release_notes_sections"`
+	Changelog         string              `yaml:"changelog"`
+	ChangelogFile     string              `yaml:"changelog_file"`
+	FloatingTags      []string            `yaml:"floating_tags"`
+	Assets            []string            `yaml:"assets"`
+	AssetChecksums    *bool               `yaml:"asset_checksums"`
+}
+
+// defaultActionConfig returns the configuration used for everything neither
+// the config file nor the inputs set.
+func defaultActionConfig() ActionConfig {
+	return ActionConfig{
+		ReleaseBranch:   "master",
+		ReleaseStrategy: ReleaseStrategyRelease,
+		TagFormat:       semver.DefaultTagFormat,
+		VersionRange:    ">0.0.0",
+		IncrementSource: IncrementSourceLabels,
+		Backend:         BackendGithub,
+		GitRemote:       "origin",
+		TagType:         TagTypeLightweight,
+		SigningFormat:   utils.SigningFormatGPG,
+		MaxRetries:      3,
+		MarkPrerelease:  MarkPrereleaseAuto,
+		ReleaseNotes:    ReleaseNotesGithub,
+		Changelog:       ChangelogNone,
+		ChangelogFile:   "CHANGELOG.md",
+		AssetChecksums:  true,
+	}
+}
+
+// LoadActionConfig merges the defaults, the config file and the action
+// inputs, in this order, and validates the result.
+func LoadActionConfig() (ActionConfig, error) {
+	actionConfig := defaultActionConfig()
+	configFile, err := readConfigFileInput(os.Getenv("INPUT_CONFIG_FILE"), os.Getenv("GITHUB_WORKSPACE"))
+	if err != nil {
+		return actionConfig, err
+	}
+	if configFile != nil {
+		configFile.apply(&actionConfig)
+	}
+	actionConfig, err = ActionConfigFromEnv(actionConfig)
+	if err != nil {
+		return actionConfig, err
+	}
+	return actionConfig, actionConfig.Validate()
+}
+
+// readConfigFileInput reads the config_file input relative to the
+// workspace. Without the input the default config file is read when it
+// exists, nil is returned when it does not.
+func readConfigFileInput(path, workspace string) (*ConfigFile, error) {
+	optional := path == ""
+	if optional {
+		path = DefaultConfigFile
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workspace, path)
+	}
+	configFile, err := ReadConfigFile(path)
+	if optional && errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return configFile, err
+}
+
+// ReadConfigFile reads and checks the config file at path.
+func ReadConfigFile(path string) (*ConfigFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var configFile ConfigFile
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&configFile); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, path, err)
+	}
+	if configFile.Version != ConfigVersion {
+		return nil, fmt.Errorf("%w: %s: version: unsupported version %d, expected %d", ErrInvalidConfig, path, configFile.Version, ConfigVersion)
+	}
+	configFile.Components, err = normalizeComponents(configFile.Components)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: components: %w", ErrInvalidConfig, path, err)
+	}
+	return &configFile, nil
+}
+
+// apply overrides actionConfig with the values set in the config file.
+func (configFile ConfigFile) apply(actionConfig *ActionConfig) {
+	for _, setting := range []struct {
+		value string
+		field *string
+	}{
+		{configFile.ReleaseBranch, &actionConfig.ReleaseBranch},
+		{configFile.ReleaseStrategy, &actionConfig.ReleaseStrategy},
+		{configFile.TagFormat, &actionConfig.TagFormat},
+		{configFile.VersionRange, &actionConfig.VersionRange},
+		{configFile.Prerelease, &actionConfig.Prerelease},
+		{configFile.IncrementSource, &actionConfig.IncrementSource},
+		{configFile.Backend, &actionConfig.Backend},
+		{configFile.GitRemote, &actionConfig.GitRemote},
+		{configFile.TagType, &actionConfig.TagType},
+		{configFile.TagMessage, &actionConfig.TagMessage},
+		{configFile.TaggerName, &actionConfig.TaggerName},
+		{configFile.TaggerEmail, &actionConfig.TaggerEmail},
+		{configFile.SigningFormat, &actionConfig.SigningFormat},
+		{configFile.MarkPrerelease, &actionConfig.MarkPrerelease},
+		{configFile.MakeLatest, &actionConfig.MakeLatest},
+		{configFile.ReleaseNotes, &actionConfig.ReleaseNotes},
+		{configFile.NotesTemplate, &actionConfig.NotesTemplate},
+		{configFile.Changelog, &actionConfig.Changelog},
+		{configFile.ChangelogFile, &actionConfig.ChangelogFile},
+	} {
+		if setting.value != "" {
+			*setting.field = setting.value
+		}
+	}
+	if configFile.ReachableTagsOnly != nil {
+		actionConfig.ReachableTagsOnly = *configFile.ReachableTagsOnly
+	}
+	if configFile.NotesSections != nil {
+		actionConfig.NotesSections = configFile.NotesSections
+	}
+	if configFile.FloatingTags != nil {
+		actionConfig.FloatingTags = configFile.FloatingTags
+	}
+	if configFile.Assets != nil {
+		actionConfig.Assets = configFile.Assets
+	}
+	if configFile.AssetChecksums != nil {
+		actionConfig.AssetChecksums = *configFile.AssetChecksums
+	}
+	if configFile.Draft != nil {
+		actionConfig.Draft = *configFile.Draft
+	}
+	if configFile.MaxRetries != nil {
+		actionConfig.MaxRetries = *configFile.MaxRetries
+	}
+	if configFile.Components != nil {
+		actionConfig.Components = configFile.Components
+	}
+	actionConfig.LabelMapping = configFile.LabelMapping
+	if configFile.LabelPolicy != "" {
+		actionConfig.LabelMapping.Policy = semver.LabelPolicy(configFile.LabelPolicy)
+	}
+}
+
+// Validate checks the whole configuration and reports all problems at once,
+// before anything talks to the repository.
+func (actionConfig ActionConfig) Validate() error {
+	errs := []error{
+		validateChoice("release_strategy", actionConfig.ReleaseStrategy, ReleaseStrategyRelease, ReleaseStrategyTag, ReleaseStrategyNone),
+		validateChoice("increment_source", actionConfig.IncrementSource, IncrementSourceLabels, IncrementSourceCommits, IncrementSourceBoth),
+		validateChoice("backend", actionConfig.Backend, BackendGithub, BackendGit),
+		validateChoice("tag_type", actionConfig.TagType, TagTypeLightweight, TagTypeAnnotated, TagTypeSigned),
+		validateChoice("signing_format", actionConfig.SigningFormat, utils.SigningFormatGPG, utils.SigningFormatSSH),
+		validateChoice("mark_prerelease", actionConfig.MarkPrerelease, MarkPrereleaseAuto, MarkPrereleaseTrue, MarkPrereleaseFalse),
+		validateChoice("release_notes", actionConfig.ReleaseNotes, ReleaseNotesGithub, ReleaseNotesTemplate),
+		validateChoice("changelog", actionConfig.Changelog, ChangelogNone, ChangelogPush, ChangelogPullRequest),
+		validateTagFormat("tag_format", actionConfig.TagFormat),
+		validateVersionRange("version_range", actionConfig.VersionRange),
+	}
+	if actionConfig.Increment != "" {
+		if _, err := semver.ParseIncrement(actionConfig.Increment); err != nil {
+			errs = append(errs, fmt.Errorf("increment: invalid value %q, expected patch, minor or major", actionConfig.Increment))
+		}
+	}
+	if actionConfig.MakeLatest != "" {
+		errs = append(errs, validateChoice("make_latest", actionConfig.MakeLatest, MakeLatestTrue, MakeLatestFalse, MakeLatestLegacy))
+	}
+	if _, err := notes.Parse(actionConfig.NotesTemplate); err != nil {
+		errs = append(errs, fmt.Errorf("release_notes_template: %w", err))
+	}
+	if err := validateReleaseNotesSections(actionConfig.NotesSections); err != nil {
+		errs = append(errs, fmt.Errorf("release_notes_sections: %w", err))
+	}
+	errs = append(errs, validateBackend(actionConfig), validateChangelog(actionConfig), validateFloatingTags(actionConfig), validateTagMessage(actionConfig.TagMessage), validateTagger(actionConfig), validateSigning(actionConfig), validateAssets(actionConfig))
+	if actionConfig.MaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("max_retries: invalid value %d, expected 0 or more", actionConfig.MaxRetries))
+	}
+	if err := actionConfig.LabelMapping.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("label_mapping: %w", err))
+	}
+	for _, component := range actionConfig.Components {
+		errs = append(errs,
+			validateTagFormat(fmt.Sprintf("components[%s].tag_format", component.Name), component.TagFormat),
+			validateVersionRange(fmt.Sprintf("components[%s].version_range", component.Name), component.VersionRange),
+		)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%w:\n%w", ErrInvalidConfig, err)
+	}
+	return nil
+}
+
+// validateBackend rejects release strategies the backend cannot carry out, a
+// checkout knows nothing about GitHub releases.
+func validateBackend(actionConfig ActionConfig) error {
+	if actionConfig.Backend == BackendGit && actionConfig.ReleaseStrategy == ReleaseStrategyRelease {
+		return errors.New("release_strategy: releases are not supported by the git backend, use tag or none")
+	}
+	return nil
+}
+
+func validateChangelog(actionConfig ActionConfig) error {
+	switch {
+	case actionConfig.Changelog == ChangelogNone:
+		return nil
+	case len(actionConfig.Components) > 0:
+		return errors.New("changelog: not supported with components")
+	case actionConfig.Backend == BackendGit:
+		return errors.New("changelog: not supported by the git backend")
+	}
+	return nil
+}
+
+func validateSigning(actionConfig ActionConfig) error {
+	switch {
+	case actionConfig.TagType != TagTypeSigned:
+		return nil
+	case actionConfig.Backend != BackendGit:
+		return errors.New("tag_type: signed tags need the git backend")
+	case actionConfig.SigningFormat == utils.SigningFormatSSH && actionConfig.SigningKey == "":
+		return errors.New("signing_key: required for ssh signatures")
+	}
+	return nil
+}
+
+func validateFloatingTags(actionConfig ActionConfig) error {
+	if len(actionConfig.FloatingTags) > 0 && len(actionConfig.Components) > 0 {
+		return errors.New("floating_tags: not supported with components")
+	}
+	var errs []error
+	for i, format := range actionConfig.FloatingTags {
+		errs = append(errs, validateFloatingTag(fmt.Sprintf("floating_tags[%d]", i), format, actionConfig.TagFormat))
+	}
+	return errors.Join(errs...)
+}
+
+func validateChoice(name, value string, allowed ...string) error {
+	for _, choice := range allowed {
+		if value == choice {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: invalid value %q, expected %s", name, value, strings.Join(allowed, ", "))
+}
+
+func validateTagFormat(name, format string) error {
+	if _, err := semver.NewTagParser(format); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func validateVersionRange(name, versionRange string) error {
+	if versionRange == "" {
+		return nil
+	}
+	if _, err := version.ParseRange(versionRange); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, dir, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DefaultConfigFile), []byte(content), 0o644))
+}
+
+func TestLoadActionConfig(t *testing.T) {
+	workspace := t.TempDir()
+	t.Setenv("GITHUB_WORKSPACE", workspace)
+	t.Setenv("GITHUB_SHA", "abc123")
+	writeConfigFile(t, workspace, `
+version: 1
+release_branch: main
+release_strategy: tag
+tag_format: "release-%major%.%minor%.%patch%"
+reachable_tags_only: true
+max_retries: 5
+label_mapping:
+  minor: ["semver:feature"]
+label_policy: highest
+components:
+  - name: auth
+    path: libs/auth
+`)
+	t.Setenv("INPUT_RELEASE_STRATEGY", "release")
+	t.Setenv("INPUT_REACHABLE_TAGS_ONLY", "false")
+
+	actionConfig, err := LoadActionConfig()
+	require.NoError(t, err)
+
+	expected := defaultActionConfig()
+	expected.ReleaseBranch = "main"                        // from the file
+	expected.ReleaseStrategy = ReleaseStrategyRelease      // input wins over the file
+	expected.TagFormat = "release-%major%.%minor%.%patch%" // from the file
+	expected.ReachableTagsOnly = false                     // input wins over the file
+	expected.MaxRetries = 5                                // from the file
+	expected.LabelMapping = semver.LabelMapping{Minor: []string{"semver:feature"}, Policy: semver.LabelPolicyHighest}
+	expected.Components = []Component{{Name: "auth", Path: "libs/auth", TagFormat: "libs/auth/v%major%.%minor%.%patch%"}}
+	expected.CustomReleaseSHA = "abc123"
+	expected.GitDir = workspace
+	assert.Equal(t, expected, actionConfig)
+}
+
+func TestLoadActionConfigWithoutFile(t *testing.T) {
+	t.Setenv("GITHUB_WORKSPACE", t.TempDir())
+	t.Setenv("INPUT_TAG_FORMAT", "%major%.%minor%.%patch%")
+
+	actionConfig, err := LoadActionConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "%major%.%minor%.%patch%", actionConfig.TagFormat)
+	assert.Equal(t, ReleaseStrategyRelease, actionConfig.ReleaseStrategy)
+
+	t.Setenv("INPUT_CONFIG_FILE", "missing.yml")
+	_, err = LoadActionConfig()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestActionConfigFromEnvBooleans(t *testing.T) {
+	t.Setenv("INPUT_DRY_RUN", "True")
+	t.Setenv("INPUT_DRAFT", "0")
+
+	actionConfig, err := ActionConfigFromEnv(defaultActionConfig())
+	require.NoError(t, err)
+	assert.True(t, actionConfig.DryRun)
+	assert.False(t, actionConfig.Draft)
+
+	t.Setenv("INPUT_DRAFT", "yes")
+	_, err = ActionConfigFromEnv(defaultActionConfig())
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.ErrorContains(t, err, `draft: invalid value "yes", expected true or false`)
+}
+
+func TestReadConfigFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{
+			name:    "Valid file",
+			content: "version: 1\nrelease_strategy: tag\n",
+		},
+		{
+			name:          "Missing version",
+			content:       "release_strategy: tag\n",
+			expectedError: "version: unsupported version 0, expected 1",
+		},
+		{
+			name:          "Unknown field",
+			content:       "version: 1\nrelease_stratgy: tag\n",
+			expectedError: "line 2: field release_stratgy not found",
+		},
+		{
+			name:          "Invalid component",
+			content:       "version: 1\ncomponents: [{name: api}]\n",
+			expectedError: "components: invalid component: component 1 needs a name and a path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfigFile(t, dir, tt.content)
+			_, err := ReadConfigFile(filepath.Join(dir, DefaultConfigFile))
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestActionConfigValidate(t *testing.T) {
+	assert.NoError(t, defaultActionConfig().Validate())
+
+	actionConfig := defaultActionConfig()
+	actionConfig.ReleaseStrategy = "relase"
+	actionConfig.TagFormat = "v%major%.%minor%"
+	actionConfig.VersionRange = ">=1.0"
+	actionConfig.Increment = "feature"
+	actionConfig.LabelMapping.Policy = "newest"
+	actionConfig.MaxRetries = -1
+	actionConfig.MakeLatest = "always"
+	actionConfig.NotesTemplate = "{{ .Tag "
+	actionConfig.TagMessage = "{{ .Version }}"
+	actionConfig.Components = []Component{{Name: "api", Path: "api", TagFormat: "api/%major%"}}
+
+	err := actionConfig.Validate()
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	for _, expected := range []string{
+		`release_strategy: invalid value "relase", expected release, tag, none`,
+		"tag_format: ",
+		"version_range: ",
+		`increment: invalid value "feature"`,
+		`label_mapping: invalid label policy "newest"`,
+		"max_retries: invalid value -1",
+		"release_notes_template: invalid release notes template",
+		"tag_message: ",
+		`make_latest: invalid value "always", expected true, false, legacy`,
+		"components[api].tag_format: ",
+	} {
+		assert.ErrorContains(t, err, expected)
+	}
+}
+
+func TestValidateBackend(t *testing.T) {
+	actionConfig := defaultActionConfig()
+	actionConfig.Backend = BackendGit
+	assert.ErrorContains(t, actionConfig.Validate(), "release_strategy: releases are not supported by the git backend")
+
+	actionConfig.ReleaseStrategy = ReleaseStrategyTag
+	assert.NoError(t, actionConfig.Validate())
+}
+
+// TestConfigSchema keeps the published JSON Schema in line with ConfigFile.
+func TestConfigSchema(t *testing.T) {
+	schemaJSON, err := os.ReadFile(filepath.Join("schema", "semver-sugar.schema.json"))
+	require.NoError(t, err)
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(schemaJSON, &schema))
+
+	var fields []string
+	configFileType := reflect.TypeOf(ConfigFile{})
+	for i := 0; i < configFileType.NumField(); i++ {
+		fields = append(fields, strings.Split(configFileType.Field(i).Tag.Get("yaml"), ",")[0])
+	}
+	var properties []string
+	for property := range schema.Properties {
+		properties = append(properties, property)
+	}
+	assert.ElementsMatch(t, fields, properties)
+}
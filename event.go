@@ -0,0 +1,244 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/actions-go/toolkit/core"
+	github "github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EventPullRequest      = "pull_request"
+	EventPush             = "push"
+	EventWorkflowDispatch = "workflow_dispatch"
+)
+
+var (
+	ErrPushRefDoesNotMatchReleaseBranch     = errors.New("pushed ref does not match release branch")
+	ErrBranchDeleted                        = errors.New("pushed branch was deleted")
+	ErrNoAssociatedPullRequest              = errors.New("no merged pull request associated with the pushed commit")
+	ErrMissingIncrement                     = errors.New("increment input is required for workflow_dispatch events")
+	ErrDispatchRefDoesNotMatchReleaseBranch = errors.New("workflow_dispatch ref does not match release branch")
+	ErrInvalidLabelMapping                  = errors.New("invalid label mapping")
+)
+
+// ParseLabelMapping parses the YAML label_mapping input, e.g.
+//
+//	minor: ["semver:feature", "type/feature*"]
+//	skip: ["no-release"]
+//
+// A non-empty policy overrides the policy of the mapping.
+func ParseLabelMapping(input string, policy string) (semver.LabelMapping, error) {
+	var mapping semver.LabelMapping
+	decoder := yaml.NewDecoder(strings.NewReader(input))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&mapping); err != nil && !errors.Is(err, io.EOF) {
+		return mapping, fmt.Errorf("%w: %w", ErrInvalidLabelMapping, err)
+	}
+	if policy != "" {
+		mapping.Policy = semver.LabelPolicy(policy)
+	}
+	if err := mapping.Validate(); err != nil {
+		return mapping, fmt.Errorf("%w: %w", ErrInvalidLabelMapping, err)
+	}
+	return mapping, nil
+}
+
+// labelSource gives access to the labels of the pull request being released.
+type labelSource interface {
+	hasSkipReleaseLabel() (bool, error)
+	increment() (string, error)
+}
+
+// eventLabels reads the labels from the pull_request event file.
+type eventLabels struct {
+	ghActionIface utils.GithubActionIface
+	eventPath     string
+	mapping       semver.LabelMapping
+}
+
+func (l eventLabels) hasSkipReleaseLabel() (bool, error) {
+	return isSkipReleaseLabelFound(l.ghActionIface, l.eventPath, l.mapping)
+}
+
+func (l eventLabels) increment() (string, error) {
+	return logResolution(l.ghActionIface.GetIncrementType(l.eventPath, l.mapping))
+}
+
+// pullRequestLabels reads the labels from a pull request fetched from the
+// API. A nil pull request has no labels.
+type pullRequestLabels struct {
+	pullRequest *github.PullRequest
+	mapping     semver.LabelMapping
+}
+
+func (l pullRequestLabels) hasSkipReleaseLabel() (bool, error) {
+	if l.pullRequest == nil {
+		return false, nil
+	}
+	for _, label := range l.pullRequest.Labels {
+		if semver.MatchLabel(l.mapping.SkipPatterns(), label.GetName()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (l pullRequestLabels) increment() (string, error) {
+	if l.pullRequest == nil {
+		return "", ErrNoAssociatedPullRequest
+	}
+	return logResolution(semver.ResolvePullRequestLabels(l.pullRequest, l.mapping))
+}
+
+// logResolution logs the increment resolved from the labels, or the labels
+// considered when none could be resolved, and returns the increment.
+func logResolution(resolution semver.LabelResolution, err error) (string, error) {
+	if err != nil {
+		core.Infof("Labels considered: [%s] with policy %s", strings.Join(resolution.Considered, ", "), resolution.Policy)
+		return "", err
+	}
+	core.Info("Resolved " + resolution.String())
+	return string(resolution.Increment), nil
+}
+
+// manualIncrement is the increment chosen by hand for workflow_dispatch
+// events.
+type manualIncrement struct {
+	incr string
+}
+
+func (m manualIncrement) hasSkipReleaseLabel() (bool, error) {
+	return false, nil
+}
+
+func (m manualIncrement) increment() (string, error) {
+	return m.incr, nil
+}
+
+// executePullRequestEventGuard runs the guard for pull_request events and
+// exits when the event should not be released.
+func executePullRequestEventGuard(ghActionIface utils.GithubActionIface, actionConfig ActionConfig) (labelSource, bool) {
+	labels := eventLabels{ghActionIface: ghActionIface, eventPath: actionConfig.EventPath, mapping: actionConfig.LabelMapping}
+	isSkipRelease, err := labels.hasSkipReleaseLabel()
+	if err != nil {
+		Exit(1)
+	}
+	core.Info("Executing PR guard now")
+	// This will prevent the action from running if the guard fails
+	err = executeGuard(ghActionIface, actionConfig.ReleaseBranch, actionConfig.EventPath, actionConfig.IncrementSource, actionConfig.LabelMapping)
+	if actionConfig.DryRun && (err == ErrPRNotClosed || err == ErrPRNotMerged) {
+		// dry runs preview the release of pull requests before they are merged
+		core.Infof("Dry run: %s, planning the release as if it was merged", err.Error())
+		return labels, isSkipRelease
+	}
+	if err != nil {
+		core.Error(err.Error())
+		switch err {
+		case ErrPRNotBase, ErrEmptyOption, ErrNoValidSemVerLabelFound:
+			if !isSkipRelease || err == ErrNoValidSemVerLabelFound {
+				Exit(1)
+			}
+		default:
+			if !isSkipRelease {
+				Exit(0)
+			}
+		}
+	}
+	return labels, isSkipRelease
+}
+
+// executePushEventGuard runs the guard for push events. It exits with 0 for
+// pushes that should not be released and with 1 when the guard fails.
+func executePushEventGuard(ghActionIface utils.GithubActionIface, actionConfig ActionConfig) (labelSource, bool) {
+	core.Info("Executing push guard now")
+	pullRequest, err := executePushGuard(ghActionIface, actionConfig.ReleaseBranch, actionConfig.EventPath)
+	if err != nil {
+		core.Error(err.Error())
+		switch err {
+		case ErrPushRefDoesNotMatchReleaseBranch, ErrBranchDeleted:
+			Exit(0)
+		default:
+			Exit(1)
+		}
+	}
+	labels := pullRequestLabels{pullRequest: pullRequest, mapping: actionConfig.LabelMapping}
+	isSkipRelease, err := labels.hasSkipReleaseLabel()
+	if err != nil {
+		core.Error(err.Error())
+		Exit(1)
+	}
+	return labels, isSkipRelease
+}
+
+// executePushGuard guards the execution of the action for pushes to the
+// release branch. It returns the merged pull request the pushed commit comes
+// from, or nil for direct pushes.
+func executePushGuard(ghActionIface utils.GithubActionIface, releaseBranch string, eventPath string) (*github.PullRequest, error) {
+	if releaseBranch == "" || eventPath == "" {
+		core.Errorf("empty releaseBranch or eventPath: releaseBranch=%s eventPath=%s", releaseBranch, eventPath)
+		return nil, ErrEmptyOption // fail
+	}
+
+	event, err := ghActionIface.ParseGithubPushEvent(eventPath)
+	if err != nil {
+		return nil, err
+	}
+	if event.GetRef() != "refs/heads/"+releaseBranch {
+		return nil, ErrPushRefDoesNotMatchReleaseBranch // skip
+	}
+	if event.GetDeleted() {
+		return nil, ErrBranchDeleted // skip
+	}
+
+	pullRequest, err := mergedPullRequest(ghActionIface, event.GetAfter(), releaseBranch)
+	if pullRequest != nil {
+		core.Infof("Pushed commit belongs to pull request #%d", pullRequest.GetNumber())
+	}
+	return pullRequest, err
+}
+
+// mergedPullRequest returns the pull request merged into releaseBranch that
+// sha belongs to, or nil when the commit was pushed directly.
+func mergedPullRequest(ghActionIface utils.GithubActionIface, sha, releaseBranch string) (*github.PullRequest, error) {
+	pullRequests, err := ghActionIface.ListPullRequestsWithCommit(sha)
+	if err != nil {
+		return nil, err
+	}
+	for _, pullRequest := range pullRequests {
+		if pullRequest.MergedAt != nil && pullRequest.GetBase().GetRef() == releaseBranch {
+			return pullRequest, nil
+		}
+	}
+	return nil, nil
+}
+
+// executeWorkflowDispatchGuard replaces the guard for manual releases: there
+// is no pull request to check, only the workflow has to run on the release
+// branch and the increment input has to be valid.
+func executeWorkflowDispatchGuard(actionConfig ActionConfig) (labelSource, bool) {
+	core.Info("Manual release, checking the release branch and increment only")
+	if actionConfig.GithubRef != "refs/heads/"+actionConfig.ReleaseBranch {
+		core.Errorf("%s: %s is not %s", ErrDispatchRefDoesNotMatchReleaseBranch.Error(), actionConfig.GithubRef, actionConfig.ReleaseBranch)
+		Exit(1)
+	}
+	if actionConfig.NextTag != "" {
+		return manualIncrement{incr: actionConfig.Increment}, false
+	}
+	if actionConfig.Increment == "" {
+		core.Error(ErrMissingIncrement.Error())
+		Exit(1)
+	}
+	increment, err := semver.ParseIncrement(actionConfig.Increment)
+	if err != nil {
+		core.Errorf("%s: %s", err.Error(), actionConfig.Increment)
+		Exit(1)
+	}
+	return manualIncrement{incr: string(increment)}, false
+}
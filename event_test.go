@@ -0,0 +1,302 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	github "github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutePushGuard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+
+	mergedPR := &github.PullRequest{
+		Number:   github.Int(7),
+		MergedAt: &github.Timestamp{},
+		Base:     &github.PullRequestBranch{Ref: github.String("main")},
+	}
+
+	tests := []struct {
+		name          string
+		releaseBranch string
+		setupMock     func()
+		expectedPR    *github.PullRequest
+		expectedError error
+	}{
+		{
+			name:          "empty releaseBranch",
+			releaseBranch: "",
+			setupMock:     func() {},
+			expectedError: ErrEmptyOption,
+		},
+		{
+			name:          "Error parsing GitHub event",
+			releaseBranch: "main",
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubPushEvent("test_event.json").Return(nil, errors.New("parsing error"))
+			},
+			expectedError: errors.New("parsing error"),
+		},
+		{
+			name:          "Push to other branch",
+			releaseBranch: "main",
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubPushEvent("test_event.json").Return(&github.PushEvent{
+					Ref: github.String("refs/heads/develop"),
+				}, nil)
+			},
+			expectedError: ErrPushRefDoesNotMatchReleaseBranch,
+		},
+		{
+			name:          "Branch deleted",
+			releaseBranch: "main",
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubPushEvent("test_event.json").Return(&github.PushEvent{
+					Ref:     github.String("refs/heads/main"),
+					Deleted: github.Bool(true),
+				}, nil)
+			},
+			expectedError: ErrBranchDeleted,
+		},
+		{
+			name:          "Push of merged pull request",
+			releaseBranch: "main",
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubPushEvent("test_event.json").Return(&github.PushEvent{
+					Ref:   github.String("refs/heads/main"),
+					After: github.String("abc123"),
+				}, nil)
+				mockGHActionIface.EXPECT().ListPullRequestsWithCommit("abc123").Return([]*github.PullRequest{
+					{Number: github.Int(6), Base: &github.PullRequestBranch{Ref: github.String("main")}},
+					mergedPR,
+				}, nil)
+			},
+			expectedPR: mergedPR,
+		},
+		{
+			name:          "Direct push",
+			releaseBranch: "main",
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubPushEvent("test_event.json").Return(&github.PushEvent{
+					Ref:   github.String("refs/heads/main"),
+					After: github.String("abc123"),
+				}, nil)
+				mockGHActionIface.EXPECT().ListPullRequestsWithCommit("abc123").Return(nil, nil)
+			},
+			expectedPR: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+			pr, err := executePushGuard(mockGHActionIface, tt.releaseBranch, "test_event.json")
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedPR, pr)
+		})
+	}
+}
+
+func TestPullRequestLabels(t *testing.T) {
+	labels := pullRequestLabels{pullRequest: &github.PullRequest{Labels: []*github.Label{
+		{Name: github.String("minor")},
+		{Name: github.String("Skip-Release")},
+	}}}
+
+	isSkipRelease, err := labels.hasSkipReleaseLabel()
+	assert.NoError(t, err)
+	assert.True(t, isSkipRelease)
+	incr, err := labels.increment()
+	assert.NoError(t, err)
+	assert.Equal(t, "minor", incr)
+
+	isSkipRelease, err = pullRequestLabels{}.hasSkipReleaseLabel()
+	assert.NoError(t, err)
+	assert.False(t, isSkipRelease)
+	_, err = pullRequestLabels{}.increment()
+	assert.Equal(t, ErrNoAssociatedPullRequest, err)
+}
+
+func TestPullRequestLabelsWithMapping(t *testing.T) {
+	labels := pullRequestLabels{
+		pullRequest: &github.PullRequest{Labels: []*github.Label{
+			{Name: github.String("semver:feature")},
+			{Name: github.String("release/none")},
+		}},
+		mapping: semver.LabelMapping{
+			Minor: []string{"semver:feature"},
+			Skip:  []string{"release/none", "no-release"},
+		},
+	}
+
+	isSkipRelease, err := labels.hasSkipReleaseLabel()
+	assert.NoError(t, err)
+	assert.True(t, isSkipRelease)
+	incr, err := labels.increment()
+	assert.NoError(t, err)
+	assert.Equal(t, "minor", incr)
+}
+
+func TestParseLabelMapping(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		policy        string
+		expected      semver.LabelMapping
+		expectedError error
+	}{
+		{
+			name:     "Empty input",
+			input:    "",
+			expected: semver.LabelMapping{},
+		},
+		{
+			name: "Mapping",
+			input: `
+major: ["semver:breaking"]
+minor: ["semver:feature", "type/feature*"]
+skip: ["no-release"]
+`,
+			expected: semver.LabelMapping{
+				Major: []string{"semver:breaking"},
+				Minor: []string{"semver:feature", "type/feature*"},
+				Skip:  []string{"no-release"},
+			},
+		},
+		{
+			name:     "Policy input overrides mapping",
+			input:    `{minor: ["semver:feature"], policy: lowest}`,
+			policy:   "highest",
+			expected: semver.LabelMapping{Minor: []string{"semver:feature"}, Policy: semver.LabelPolicyHighest},
+		},
+		{
+			name:          "Invalid policy",
+			policy:        "newest",
+			expectedError: ErrInvalidLabelMapping,
+		},
+		{
+			name:          "Unknown increment",
+			input:         `feature: ["semver:feature"]`,
+			expectedError: ErrInvalidLabelMapping,
+		},
+		{
+			name:          "Invalid pattern",
+			input:         `patch: ["type/[bug"]`,
+			expectedError: ErrInvalidLabelMapping,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, err := ParseLabelMapping(tt.input, tt.policy)
+			assert.ErrorIs(t, err, tt.expectedError)
+			if tt.expectedError == nil {
+				assert.Equal(t, tt.expected, mapping)
+			}
+		})
+	}
+}
+
+func TestExecutePushEventGuard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+
+	defer func() { osExit = os.Exit }()
+	osExit = func(code int) {
+		panic(code)
+	}
+
+	tests := []struct {
+		name         string
+		setupMock    func()
+		expectedExit int
+	}{
+		{
+			name: "Push to other branch",
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubPushEvent("test_event.json").Return(&github.PushEvent{
+					Ref: github.String("refs/heads/develop"),
+				}, nil)
+			},
+			expectedExit: 0,
+		},
+		{
+			name: "Error parsing GitHub event",
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubPushEvent("test_event.json").Return(nil, errors.New("parsing error"))
+			},
+			expectedExit: 1,
+		},
+		{
+			name: "Error listing pull requests",
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubPushEvent("test_event.json").Return(&github.PushEvent{
+					Ref:   github.String("refs/heads/main"),
+					After: github.String("abc123"),
+				}, nil)
+				mockGHActionIface.EXPECT().ListPullRequestsWithCommit("abc123").Return(nil, errors.New("api error"))
+			},
+			expectedExit: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+			exitCode := -1
+			func() {
+				defer func() {
+					if code, ok := recover().(int); ok {
+						exitCode = code
+					}
+				}()
+				executePushEventGuard(mockGHActionIface, ActionConfig{ReleaseBranch: "main", EventPath: "test_event.json"})
+			}()
+			assert.Equal(t, tt.expectedExit, exitCode)
+		})
+	}
+}
+
+func TestExecuteWorkflowDispatchGuard(t *testing.T) {
+	defer func() { osExit = os.Exit }()
+	osExit = func(code int) {
+		panic(code)
+	}
+
+	tests := []struct {
+		name         string
+		githubRef    string
+		increment    string
+		expectedExit int
+	}{
+		{name: "Release branch", githubRef: "refs/heads/main", increment: "minor", expectedExit: -1},
+		{name: "Other branch", githubRef: "refs/heads/feature", increment: "minor", expectedExit: 1},
+		{name: "Tag", githubRef: "refs/tags/v1.0.0", increment: "minor", expectedExit: 1},
+		{name: "Missing increment", githubRef: "refs/heads/main", expectedExit: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode := -1
+			func() {
+				defer func() {
+					if code, ok := recover().(int); ok {
+						exitCode = code
+					}
+				}()
+				executeWorkflowDispatchGuard(ActionConfig{ReleaseBranch: "main", GithubRef: tt.githubRef, Increment: tt.increment})
+			}()
+			assert.Equal(t, tt.expectedExit, exitCode)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/actions-go/toolkit/core"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+)
+
+// ParseFloatingTags parses the comma or newline separated floating_tags
+// input, e.g. "v%major%, v%major%.%minor%".
+func ParseFloatingTags(input string) []string {
+	var formats []string
+	for _, format := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if format = strings.TrimSpace(format); format != "" {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
+
+// validateFloatingTag checks that format only uses the %major% and %minor%
+// placeholders and that the tags it makes are never read as versions by
+// tagFormat.
+func validateFloatingTag(name, format, tagFormat string) error {
+	if !strings.Contains(format, "%major%") {
+		return fmt.Errorf("%s: %q is missing %%major%%", name, format)
+	}
+	rest := strings.NewReplacer("%major%", "", "%minor%", "").Replace(format)
+	if placeholder := semver.PlaceholderPattern.FindString(rest); placeholder != "" {
+		return fmt.Errorf("%s: %q: unsupported placeholder %s, only %%major%% and %%minor%% are allowed", name, format, placeholder)
+	}
+	sample, err := semver.ParseVersion("1.2.3")
+	if err != nil {
+		return err
+	}
+	if _, err := semver.ParseTag(tagFormat, sample.Format(format)); err == nil {
+		return fmt.Errorf("%s: %q makes tags matching tag_format %q", name, format, tagFormat)
+	}
+	return nil
+}
+
+// floatingRange is the version range of the releases a floating tag follows,
+// e.g. ">=1.4.0 <1.5.0-0" for v%major%.%minor% and 1.4.2.
+func floatingRange(format string, version semver.Version) string {
+	if strings.Contains(format, "%minor%") {
+		return fmt.Sprintf(">=%d.%d.0 <%d.%d.0-0", version.Major(), version.Minor(), version.Major(), version.Minor()+1)
+	}
+	return fmt.Sprintf(">=%d.0.0 <%d.0.0-0", version.Major(), version.Major()+1)
+}
+
+// movesFloatingTags reports whether the release of the next tag moves the
+// floating tags. Pre-releases and drafts do not.
+func movesFloatingTags(actionConfig ActionConfig, nextVersion semver.Version) bool {
+	switch {
+	case len(actionConfig.FloatingTags) == 0, actionConfig.ReleaseStrategy == ReleaseStrategyNone:
+		return false
+	case nextVersion.IsPrerelease(), actionConfig.Draft && actionConfig.ReleaseStrategy == ReleaseStrategyRelease:
+		core.Infof("%s is a pre-release or draft, floating tags are not moved", actionConfig.NextTag)
+		return false
+	}
+	return true
+}
+
+// executeFloatingTags moves the floating tags of the released next tag, e.g.
+// v1 and v1.4 for v1.4.2, to the release SHA. A release that is not the
+// highest tag of a floating tag does not move it, e.g. v1.3.5 released after
+// v1.4.0 keeps v1 at v1.4.0.
+func executeFloatingTags(ghActionIface utils.GithubActionIface, actionConfig ActionConfig) error {
+	if len(actionConfig.FloatingTags) == 0 {
+		return nil
+	}
+	nextVersion, err := semver.ParseTag(actionConfig.TagFormat, actionConfig.NextTag)
+	if err != nil || !movesFloatingTags(actionConfig, nextVersion) {
+		return err
+	}
+
+	for _, format := range actionConfig.FloatingTags {
+		tag := nextVersion.Format(format)
+		latestTag, err := ghActionIface.GetGithubLatestTag(floatingRange(format, nextVersion), actionConfig.TagFormat, "")
+		if err != nil && !errors.Is(err, utils.ErrNoMatchingTag) {
+			return err
+		}
+		if latestTag != actionConfig.NextTag {
+			core.Infof("Not moving %s, %s is the latest tag of it", tag, latestTag)
+			continue
+		}
+		if err := ghActionIface.MoveGithubTag(tag, actionConfig.CustomReleaseSHA); err != nil {
+			return fmt.Errorf("moving floating tag %s: %w", tag, err)
+		}
+		core.Infof("Floating tag %s points to %s", tag, actionConfig.CustomReleaseSHA)
+	}
+	return nil
+}
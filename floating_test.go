@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFloatingTags(t *testing.T) {
+	assert.Equal(t, []string{"v%major%", "v%major%.%minor%"}, ParseFloatingTags("v%major%, v%major%.%minor%"))
+	assert.Equal(t, []string{"v%major%", "v%major%.%minor%"}, ParseFloatingTags("v%major%\nv%major%.%minor%\n"))
+	assert.Empty(t, ParseFloatingTags(" , "))
+}
+
+func TestValidateFloatingTag(t *testing.T) {
+	assert.NoError(t, validateFloatingTag("floating_tags[0]", "v%major%", semver.DefaultTagFormat))
+	assert.NoError(t, validateFloatingTag("floating_tags[0]", "v%major%.%minor%", semver.DefaultTagFormat))
+	assert.ErrorContains(t, validateFloatingTag("floating_tags[0]", "latest", semver.DefaultTagFormat), "missing %major%")
+	assert.ErrorContains(t, validateFloatingTag("floating_tags[0]", "v%major%.%patch%", semver.DefaultTagFormat), "unsupported placeholder %patch%")
+	assert.ErrorContains(t, validateFloatingTag("floating_tags[0]", "v%major%.%minor%.0", semver.DefaultTagFormat), "matching tag_format")
+}
+
+func TestFloatingRange(t *testing.T) {
+	version, err := semver.ParseVersion("1.4.2")
+	require.NoError(t, err)
+	assert.Equal(t, ">=1.0.0 <2.0.0-0", floatingRange("v%major%", version))
+	assert.Equal(t, ">=1.4.0 <1.5.0-0", floatingRange("v%major%.%minor%", version))
+}
+
+func TestExecuteFloatingTags(t *testing.T) {
+	actionConfig := ActionConfig{
+		ReleaseStrategy:  ReleaseStrategyRelease,
+		TagFormat:        semver.DefaultTagFormat,
+		NextTag:          "v1.3.5",
+		CustomReleaseSHA: "abc123",
+		FloatingTags:     []string{"v%major%", "v%major%.%minor%"},
+	}
+
+	tests := []struct {
+		name      string
+		nextTag   string
+		draft     bool
+		setupMock func(mockGHActionIface *utils.MockGithubActionIface)
+	}{
+		{
+			name:    "Highest version",
+			nextTag: "v1.3.5",
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">=1.0.0 <2.0.0-0", semver.DefaultTagFormat, "").Return("v1.3.5", nil)
+				mockGHActionIface.EXPECT().MoveGithubTag("v1", "abc123").Return(nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">=1.3.0 <1.4.0-0", semver.DefaultTagFormat, "").Return("v1.3.5", nil)
+				mockGHActionIface.EXPECT().MoveGithubTag("v1.3", "abc123").Return(nil)
+			},
+		},
+		{
+			name:    "Older release line",
+			nextTag: "v1.3.5",
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">=1.0.0 <2.0.0-0", semver.DefaultTagFormat, "").Return("v1.4.0", nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">=1.3.0 <1.4.0-0", semver.DefaultTagFormat, "").Return("v1.3.5", nil)
+				mockGHActionIface.EXPECT().MoveGithubTag("v1.3", "abc123").Return(nil)
+			},
+		},
+		{
+			name:      "Pre-release",
+			nextTag:   "v2.0.0-rc.1",
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {},
+		},
+		{
+			name:      "Draft",
+			nextTag:   "v1.3.5",
+			draft:     true,
+			setupMock: func(mockGHActionIface *utils.MockGithubActionIface) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+			tt.setupMock(mockGHActionIface)
+
+			config := actionConfig
+			config.NextTag = tt.nextTag
+			config.Draft = tt.draft
+			assert.NoError(t, executeFloatingTags(mockGHActionIface, config))
+		})
+	}
+}
@@ -5,8 +5,14 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/actions-go/toolkit/core"
+	"github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/notes"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
 	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
 )
 
@@ -31,29 +37,174 @@ type ActionConfig struct {
 	GithubRepository string
 	GithubToken      string
 	CurrentTag       string
+	Prerelease       string
+	RunNumber        string
+	IncrementSource  string
+	EventName        string
+	GithubRef        string
+	DryRun           bool
+	Backend          string
+	GitDir           string
+	GitRemote        string
+	TagType          string
+	// TagMessage is the text/template of the message of annotated tags,
+	// DefaultTagMessage when empty.
+	TagMessage string
+	// TaggerName and TaggerEmail identify the tagger of annotated tags.
+	TaggerName  string
+	TaggerEmail string
+	// SigningFormat is the format of the signatures of signed tags,
+	// utils.SigningFormatGPG or utils.SigningFormatSSH.
+	SigningFormat string
+	// SigningKey is the private key, or the key ID or path, signed tags are
+	// signed with.
+	SigningKey string
+	// ReachableTagsOnly only considers tags reachable from CustomReleaseSHA
+	// when looking for the latest tag.
+	ReachableTagsOnly bool
+	// Components are released independently instead of the repository as a
+	// whole when set.
+	Components []Component
+	// ComponentPath limits the commits of a component release to the ones
+	// changing files below it.
+	ComponentPath string
+	// LabelMapping maps pull request labels to increments and skipping.
+	LabelMapping semver.LabelMapping
+	// MaxRetries is how often the next tag is computed again when a
+	// concurrent run created it first.
+	MaxRetries int
+	// Draft creates releases as drafts.
+	Draft bool
+	// MarkPrerelease marks releases as pre-releases: "auto" for versions
+	// with a pre-release part, "true" or "false".
+	MarkPrerelease string
+	// MakeLatest sets whether a release becomes the latest release of the
+	// repository: "true", "false" or "legacy".
+	MakeLatest string
+	// ReleaseNotes is where release notes come from, ReleaseNotesGithub or
+	// ReleaseNotesTemplate.
+	ReleaseNotes string
+	// NotesTemplate is the text/template of the release notes,
+	// notes.DefaultTemplate when empty.
+	NotesTemplate string
+	// NotesSections group the release notes, notes.DefaultSections when
+	// empty.
+	NotesSections []notes.Section
+	// Changelog is how the changelog file is updated: ChangelogNone,
+	// ChangelogPush or ChangelogPullRequest.
+	Changelog string
+	// ChangelogFile is the path of the changelog in the repository.
+	ChangelogFile string
+	// FloatingTags are the formats of the tags moved to every release, e.g.
+	// "v%major%" and "v%major%.%minor%".
+	FloatingTags []string
+	// Assets are the glob patterns of the files uploaded to the release.
+	Assets []string
+	// AssetChecksums uploads the ChecksumsFile of the assets along with
+	// them.
+	AssetChecksums bool
 }
 
-func ActionConfigFromEnv() ActionConfig {
-	githubSHA := os.Getenv("GITHUB_SHA")
-	customReleaseSHA := os.Getenv("INPUT_CUSTOM_RELEASE_SHA")
-	if customReleaseSHA != "" {
-		githubSHA = customReleaseSHA
+// ActionConfigFromEnv overrides actionConfig with the action inputs set in
+// the environment and fills in the values of the GitHub Actions environment.
+func ActionConfigFromEnv(actionConfig ActionConfig) (ActionConfig, error) {
+	for name, field := range map[string]*string{
+		"INPUT_RELEASE_BRANCH":         &actionConfig.ReleaseBranch,
+		"INPUT_RELEASE_STRATEGY":       &actionConfig.ReleaseStrategy,
+		"INPUT_NEXT_TAG":               &actionConfig.NextTag,
+		"INPUT_TAG_FORMAT":             &actionConfig.TagFormat,
+		"INPUT_GITHUB_API_URL":         &actionConfig.GithubApiUrl,
+		"INPUT_GITHUB_UPLOADS_URL":     &actionConfig.GithubUploadsUrl,
+		"INPUT_VERSION_RANGE":          &actionConfig.VersionRange,
+		"INPUT_INCREMENT":              &actionConfig.Increment,
+		"INPUT_PRERELEASE":             &actionConfig.Prerelease,
+		"INPUT_INCREMENT_SOURCE":       &actionConfig.IncrementSource,
+		"INPUT_BACKEND":                &actionConfig.Backend,
+		"INPUT_GIT_REMOTE":             &actionConfig.GitRemote,
+		"INPUT_TAG_TYPE":               &actionConfig.TagType,
+		"INPUT_TAG_MESSAGE":            &actionConfig.TagMessage,
+		"INPUT_TAGGER_NAME":            &actionConfig.TaggerName,
+		"INPUT_TAGGER_EMAIL":           &actionConfig.TaggerEmail,
+		"INPUT_SIGNING_FORMAT":         &actionConfig.SigningFormat,
+		"INPUT_SIGNING_KEY":            &actionConfig.SigningKey,
+		"INPUT_MARK_PRERELEASE":        &actionConfig.MarkPrerelease,
+		"INPUT_MAKE_LATEST":            &actionConfig.MakeLatest,
+		"INPUT_RELEASE_NOTES":          &actionConfig.ReleaseNotes,
+		"INPUT_RELEASE_NOTES_TEMPLATE": &actionConfig.NotesTemplate,
+		"INPUT_CHANGELOG":              &actionConfig.Changelog,
+		"INPUT_CHANGELOG_FILE":         &actionConfig.ChangelogFile,
+	} {
+		if value := os.Getenv(name); value != "" {
+			*field = value
+		}
+	}
+	for name, field := range map[string]*bool{
+		"INPUT_DRY_RUN":             &actionConfig.DryRun,
+		"INPUT_REACHABLE_TAGS_ONLY": &actionConfig.ReachableTagsOnly,
+		"INPUT_DRAFT":               &actionConfig.Draft,
+		"INPUT_ASSET_CHECKSUMS":     &actionConfig.AssetChecksums,
+	} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return actionConfig, fmt.Errorf("%w: %s: invalid value %q, expected true or false", ErrInvalidConfig, strings.ToLower(strings.TrimPrefix(name, "INPUT_")), value)
+		}
+		*field = parsed
+	}
+
+	actionConfig.CustomReleaseSHA = os.Getenv("GITHUB_SHA")
+	if customReleaseSHA := os.Getenv("INPUT_CUSTOM_RELEASE_SHA"); customReleaseSHA != "" {
+		actionConfig.CustomReleaseSHA = customReleaseSHA
 	}
-	return ActionConfig{
-		ReleaseBranch:    os.Getenv("INPUT_RELEASE_BRANCH"),
-		ReleaseStrategy:  os.Getenv("INPUT_RELEASE_STRATEGY"),
-		NextTag:          os.Getenv("INPUT_NEXT_TAG"),
-		TagFormat:        os.Getenv("INPUT_TAG_FORMAT"),
-		GithubApiUrl:     os.Getenv("INPUT_GITHUB_API_URL"),
-		GithubUploadsUrl: os.Getenv("INPUT_GITHUB_UPLOADS_URL"),
-		CustomReleaseSHA: githubSHA,
-		VersionRange:     os.Getenv("INPUT_VERSION_RANGE"),
-		Increment:        os.Getenv("INPUT_INCREMENT"),
-		EventPath:        os.Getenv("GITHUB_EVENT_PATH"),
-		GithubRepository: os.Getenv("GITHUB_REPOSITORY"),
-		GithubToken:      os.Getenv("GITHUB_TOKEN"),
-		CurrentTag:       "",
+	actionConfig.EventPath = os.Getenv("GITHUB_EVENT_PATH")
+	actionConfig.EventName = os.Getenv("GITHUB_EVENT_NAME")
+	actionConfig.GithubRef = os.Getenv("GITHUB_REF")
+	actionConfig.GithubRepository = os.Getenv("GITHUB_REPOSITORY")
+	actionConfig.GithubToken = os.Getenv("GITHUB_TOKEN")
+	actionConfig.RunNumber = os.Getenv("GITHUB_RUN_NUMBER")
+	actionConfig.GitDir = os.Getenv("GITHUB_WORKSPACE")
+
+	if input := os.Getenv("INPUT_COMPONENTS"); input != "" {
+		components, err := ParseComponents(input)
+		if err != nil {
+			return actionConfig, err
+		}
+		actionConfig.Components = components
 	}
+	if input := os.Getenv("INPUT_LABEL_MAPPING"); input != "" {
+		labelMapping, err := ParseLabelMapping(input, "")
+		if err != nil {
+			return actionConfig, err
+		}
+		actionConfig.LabelMapping = labelMapping
+	}
+	if policy := os.Getenv("INPUT_LABEL_POLICY"); policy != "" {
+		actionConfig.LabelMapping.Policy = semver.LabelPolicy(policy)
+	}
+	if input := os.Getenv("INPUT_RELEASE_NOTES_SECTIONS"); input != "" {
+		sections, err := ParseReleaseNotesSections(input)
+		if err != nil {
+			return actionConfig, err
+		}
+		actionConfig.NotesSections = sections
+	}
+	if input := os.Getenv("INPUT_FLOATING_TAGS"); input != "" {
+		actionConfig.FloatingTags = ParseFloatingTags(input)
+	}
+	if input := os.Getenv("INPUT_ASSETS"); input != "" {
+		actionConfig.Assets = ParseAssets(input)
+	}
+	if input := os.Getenv("INPUT_MAX_RETRIES"); input != "" {
+		maxRetries, err := strconv.Atoi(input)
+		if err != nil {
+			return actionConfig, fmt.Errorf("%w: max_retries: invalid value %q", ErrInvalidConfig, input)
+		}
+		actionConfig.MaxRetries = maxRetries
+	}
+	return actionConfig, nil
 }
 
 type ReleaseStrategy string
@@ -64,6 +215,41 @@ const (
 	ReleaseStrategyNone    = "none"
 )
 
+const (
+	BackendGithub = "github"
+	BackendGit    = "git"
+)
+
+// GitRemoteNone as git remote keeps the tags of the git backend in the
+// checkout.
+const GitRemoteNone = "none"
+
+const (
+	TagTypeLightweight = "lightweight"
+	TagTypeAnnotated   = "annotated"
+	// TagTypeSigned creates annotated tags signed with SigningKey, git
+	// backend only.
+	TagTypeSigned = "signed"
+)
+
+const (
+	MarkPrereleaseAuto  = "auto"
+	MarkPrereleaseTrue  = "true"
+	MarkPrereleaseFalse = "false"
+)
+
+const (
+	MakeLatestTrue   = "true"
+	MakeLatestFalse  = "false"
+	MakeLatestLegacy = "legacy"
+)
+
+const (
+	IncrementSourceLabels  = "labels"
+	IncrementSourceCommits = "commits"
+	IncrementSourceBoth    = "both"
+)
+
 var (
 	ErrEmptyOption                      = errors.New("empty option")
 	ErrPRNotClosed                      = errors.New("pull request is not closed")
@@ -74,8 +260,9 @@ var (
 )
 
 // ExecuteGuard guards the execution of the action based on the pull request
-// state and labels.
-func executeGuard(ghActionIface utils.GithubActionIface, releaseBranch string, eventPath string) error {
+// state and labels. Labels are only required when the increment comes from
+// labels alone.
+func executeGuard(ghActionIface utils.GithubActionIface, releaseBranch string, eventPath string, incrementSource string, labelMapping semver.LabelMapping) error {
 	if releaseBranch == "" || eventPath == "" {
 		core.Errorf("empty releaseBranch or eventPath: releaseBranch=%s eventPath=%s", releaseBranch, eventPath)
 		return ErrEmptyOption // fail
@@ -101,41 +288,189 @@ func executeGuard(ghActionIface utils.GithubActionIface, releaseBranch string, e
 		return ErrBaseRefDoesNotMatchReleaseBranch // skip
 
 	}
-	_, err = ghActionIface.GetIncrementType(eventPath)
+	if incrementSource != "" && incrementSource != IncrementSourceLabels {
+		return nil
+	}
+	_, err = ghActionIface.GetIncrementType(eventPath, labelMapping)
 	if err != nil {
 		return ErrNoValidSemVerLabelFound // here it should fail
 	}
 	return nil
 }
 
-func executeCreateRelease(ghActionIface utils.GithubActionIface, githubSHA, currentTag, nextTag, releaseStrategy string) error {
+// TagConflictError is returned when the tag of a release already exists at
+// another commit.
+type TagConflictError struct {
+	Tag         string
+	SHA         string
+	ExistingSHA string
+}
+
+func (e *TagConflictError) Error() string {
+	return fmt.Sprintf("tag %s already exists at %s instead of %s", e.Tag, e.ExistingSHA, e.SHA)
+}
+
+// executeCreateRelease creates the tag or release of nextTag and returns the
+// release, nil for the other release strategies. Releases whose tag already
+// points to githubSHA, e.g. when a workflow is re-run after a partial
+// failure, are completed instead of failing.
+func executeCreateRelease(ghActionIface utils.GithubActionIface, githubSHA, currentTag, nextTag, releaseStrategy string, options utils.ReleaseOptions) (*github.RepositoryRelease, error) {
 	switch releaseStrategy {
 	case ReleaseStrategyNone:
-		return nil
+		return nil, nil
 	case ReleaseStrategyRelease:
-		core.Debug("Creating release now")
-		if err := ghActionIface.CreateGithubRelease(nextTag, githubSHA); err != nil {
-			return err
+		return createRelease(ghActionIface, githubSHA, currentTag, nextTag, options)
+	case ReleaseStrategyTag:
+		tagCreated, err := isTagCreated(ghActionIface, nextTag, githubSHA)
+		if err != nil {
+			return nil, err
+		}
+		if tagCreated {
+			core.Infof("Tag %s already exists at %s, skipping tag creation", nextTag, githubSHA)
+			return nil, nil
+		}
+		return nil, ghActionIface.CreateGithubTag(nextTag, githubSHA, options.Tag)
+	}
+	return nil, errors.New("invalid release strategy")
+}
+
+// createRelease creates the release of nextTag unless it exists already.
+// Without rendered release notes, GitHub generates them since currentTag,
+// not since its latest release, which may be of another component.
+func createRelease(ghActionIface utils.GithubActionIface, githubSHA, currentTag, nextTag string, options utils.ReleaseOptions) (*github.RepositoryRelease, error) {
+	tagCreated, err := isTagCreated(ghActionIface, nextTag, githubSHA)
+	if err != nil {
+		return nil, err
+	}
+	if !tagCreated && (options.Draft || options.Tag.Annotated) {
+		// drafts do not create their tag before they are published, without
+		// it the next run would compute the same tag again, and GitHub only
+		// creates lightweight tags for releases
+		core.Debug("Creating tag of release now")
+		if err := ghActionIface.CreateGithubTag(nextTag, githubSHA, options.Tag); err != nil {
+			return nil, err
 		}
+	}
+	if tagCreated {
+		releaseExists, err := ghActionIface.ReleaseExists(nextTag)
+		if err != nil {
+			return nil, err
+		}
+		if releaseExists {
+			core.Infof("Release %s already exists, skipping release creation", nextTag)
+			return ghActionIface.GetGithubRelease(nextTag)
+		}
+	}
+	if options.Body == "" {
 		core.Debug("Generating release notes now")
-		if _, resp, err := ghActionIface.GenerateReleaseNotes(nextTag, currentTag); err != nil {
-			bodyBytes, _ := io.ReadAll(resp.Response.Body)
-			core.Debug(string(bodyBytes))
-			return err
+		releaseNotes, resp, err := ghActionIface.GenerateReleaseNotes(nextTag, githubSHA, currentTag)
+		if err != nil {
+			if resp != nil {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				core.Debug(string(bodyBytes))
+			}
+			return nil, err
 		}
-	case ReleaseStrategyTag:
-		if err := ghActionIface.CreateGithubTag(nextTag, githubSHA); err != nil {
-			return err
+		options.Body = releaseNotes.Body
+	}
+	core.Debug("Creating release now")
+	return ghActionIface.CreateGithubRelease(nextTag, githubSHA, options)
+}
+
+// releaseOptions returns the options of the release of actionConfig's next
+// tag. With MarkPrereleaseAuto, next tags with a pre-release part are marked
+// as pre-releases.
+func releaseOptions(actionConfig ActionConfig) utils.ReleaseOptions {
+	options := utils.ReleaseOptions{
+		Draft:      actionConfig.Draft,
+		Prerelease: actionConfig.MarkPrerelease == MarkPrereleaseTrue,
+		MakeLatest: actionConfig.MakeLatest,
+	}
+	if actionConfig.MarkPrerelease == "" || actionConfig.MarkPrerelease == MarkPrereleaseAuto {
+		nextVersion, err := semver.ParseTag(actionConfig.TagFormat, actionConfig.NextTag)
+		options.Prerelease = err == nil && nextVersion.IsPrerelease()
+	}
+	return options
+}
+
+// isTagCreated reports whether tag already points to sha. A tag pointing to
+// another commit is a TagConflictError.
+func isTagCreated(ghActionIface utils.GithubActionIface, tag, sha string) (bool, error) {
+	existingSHA, err := ghActionIface.GetTagSHA(tag)
+	if errors.Is(err, utils.ErrTagNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if existingSHA != sha {
+		return false, &TagConflictError{Tag: tag, SHA: sha, ExistingSHA: existingSHA}
+	}
+	return true, nil
+}
+
+// resolveIncrement finds the increment from the pull request labels, the
+// Conventional Commits since the latest tag or, for "both", the larger of
+// the two. With "both", pull requests without increment labels and pushes
+// without a pull request use the commits alone. Manual releases use the
+// increment input.
+func resolveIncrement(ghActionIface utils.GithubActionIface, actionConfig ActionConfig, labels labelSource) (string, error) {
+	if actionConfig.EventName == EventWorkflowDispatch {
+		// the increment chosen by hand wins over any other source
+		return labels.increment()
+	}
+	switch actionConfig.IncrementSource {
+	case "", IncrementSourceLabels:
+		incr, err := labels.increment()
+		if errors.Is(err, ErrNoAssociatedPullRequest) {
+			core.Info("No pull request is associated with the pushed commit, using commits instead of labels")
+			return commitsIncrement(ghActionIface, actionConfig)
 		}
-	default:
-		return errors.New("invalid release strategy")
+		return incr, err
+	case IncrementSourceCommits:
+		return commitsIncrement(ghActionIface, actionConfig)
+	case IncrementSourceBoth:
+		labelsIncr, labelsErr := labels.increment()
+		if labelsErr != nil && !errors.Is(labelsErr, ErrNoAssociatedPullRequest) && !errors.Is(labelsErr, semver.ErrNoValidLabels) {
+			// only missing labels fall back to the commits, conflicting
+			// ones have to be fixed
+			return "", labelsErr
+		}
+		commitsIncr, commitsErr := commitsIncrement(ghActionIface, actionConfig)
+		switch {
+		case labelsErr != nil && commitsErr != nil:
+			return "", errors.Join(labelsErr, commitsErr)
+		case labelsErr != nil:
+			return commitsIncr, nil
+		case commitsErr != nil:
+			return labelsIncr, nil
+		}
+		return string(semver.MaxIncrement(semver.Increment(labelsIncr), semver.Increment(commitsIncr))), nil
 	}
+	return "", fmt.Errorf("invalid increment source: %s", actionConfig.IncrementSource)
+}
 
-	return nil
+func commitsIncrement(ghActionIface utils.GithubActionIface, actionConfig ActionConfig) (string, error) {
+	commits, err := listReleaseCommits(ghActionIface, actionConfig)
+	if err != nil {
+		return "", err
+	}
+	core.Debugf("Found %d commits between %s and %s", len(commits), actionConfig.CurrentTag, actionConfig.CustomReleaseSHA)
+	increment, err := semver.ExtractSemVerIncrementFromCommits(commits)
+	return string(increment), err
 }
 
-func isSkipReleaseLabelFound(ghActionIface utils.GithubActionIface, eventPath string) (bool, error) {
-	for _, labelName := range []string{"skip-release", "skipRelease"} {
+// listReleaseCommits returns the commits between the latest tag and the
+// release SHA, for components only the ones changing files below their path.
+func listReleaseCommits(ghActionIface utils.GithubActionIface, actionConfig ActionConfig) ([]*github.RepositoryCommit, error) {
+	if actionConfig.ComponentPath != "" {
+		return ghActionIface.ListPathCommits(actionConfig.CurrentTag, actionConfig.CustomReleaseSHA, actionConfig.ComponentPath)
+	}
+	return ghActionIface.ListCommits(actionConfig.CurrentTag, actionConfig.CustomReleaseSHA)
+}
+
+func isSkipReleaseLabelFound(ghActionIface utils.GithubActionIface, eventPath string, labelMapping semver.LabelMapping) (bool, error) {
+	for _, labelName := range labelMapping.SkipPatterns() {
 		isSkipRelease, err := ghActionIface.DoesLabelExist(labelName, eventPath)
 		if err != nil {
 			return false, err
@@ -147,74 +482,277 @@ func isSkipReleaseLabelFound(ghActionIface utils.GithubActionIface, eventPath st
 	return false, nil
 }
 
-// TODO: ./main.go:157:10: elseif: can replace 'else {if cond {}}' with 'else if cond {}'
-func executeAction(ghActionIface utils.GithubActionIface, actionConfig ActionConfig) {
-	isSkipRelease, err := isSkipReleaseLabelFound(ghActionIface, actionConfig.EventPath)
+// executeNextTag fills in the latest tag, the increment and the next tag of
+// actionConfig. A next tag given as input is kept as is.
+func executeNextTag(ghActionIface utils.GithubActionIface, actionConfig ActionConfig, labels labelSource) (ActionConfig, error) {
+	actionConfig, err := executeLatestTag(ghActionIface, actionConfig)
 	if err != nil {
-		Exit(1)
+		return actionConfig, err
+	}
+	if actionConfig.NextTag != "" {
+		return actionConfig, nil
+	}
+	actionConfig, released, err := executeAlreadyReleased(ghActionIface, actionConfig)
+	if err != nil || released {
+		return actionConfig, err
 	}
-	core.Info("Executing PR guard now")
-	// This will prevent the action from running if the guard fails
-	err = executeGuard(ghActionIface, actionConfig.ReleaseBranch, actionConfig.EventPath)
+	return executeBump(ghActionIface, actionConfig, labels)
+}
+
+// executeAlreadyReleased handles re-runs for a commit that was tagged
+// already: the latest tag becomes the next tag again instead of being bumped
+// a second time, and the tag before it the previous tag.
+func executeAlreadyReleased(ghActionIface utils.GithubActionIface, actionConfig ActionConfig) (ActionConfig, bool, error) {
+	latestTagSHA, err := ghActionIface.GetTagSHA(actionConfig.CurrentTag)
 	if err != nil {
-		core.Error(err.Error())
-		switch err {
-		case ErrPRNotBase, ErrEmptyOption, ErrNoValidSemVerLabelFound:
-			if !isSkipRelease || err == ErrNoValidSemVerLabelFound {
-				Exit(1)
-			}
-		default:
-			if !isSkipRelease {
-				Exit(0)
-			}
+		return actionConfig, false, err
+	}
+	if latestTagSHA != actionConfig.CustomReleaseSHA {
+		// with a pushed changelog the tag is on the changelog commit
+		if actionConfig.Changelog != ChangelogPush {
+			return actionConfig, false, nil
+		}
+		commits, err := ghActionIface.ListCommits(actionConfig.CustomReleaseSHA, latestTagSHA)
+		if err != nil {
+			return actionConfig, false, err
+		}
+		if changelogCommit(commits, actionConfig.ChangelogFile, actionConfig.CurrentTag, actionConfig.CustomReleaseSHA) != latestTagSHA {
+			return actionConfig, false, nil
 		}
+		actionConfig.CustomReleaseSHA = latestTagSHA
 	}
+	core.Infof("Commit %s is already tagged %s, completing that release instead of bumping again", actionConfig.CustomReleaseSHA, actionConfig.CurrentTag)
 
-	core.Debug("Executing next tag calculation now")
+	latestVersion, err := semver.ParseTag(actionConfig.TagFormat, actionConfig.CurrentTag)
+	if err != nil {
+		return actionConfig, false, err
+	}
+	previousRange, err := semver.RangeBelow(actionConfig.VersionRange, latestVersion)
+	if err != nil {
+		return actionConfig, false, err
+	}
+	previousTag, err := ghActionIface.GetGithubLatestTag(previousRange, actionConfig.TagFormat, latestTagReachableFrom(actionConfig))
+	if err != nil && !errors.Is(err, utils.ErrNoMatchingTag) {
+		return actionConfig, false, err
+	}
+	actionConfig.NextTag = actionConfig.CurrentTag
+	actionConfig.CurrentTag = previousTag
+	return actionConfig, true, nil
+}
+
+// executeLatestTag fills in the latest tag of actionConfig.
+func executeLatestTag(ghActionIface utils.GithubActionIface, actionConfig ActionConfig) (ActionConfig, error) {
 	core.Debug("Getting latest tag from github repository")
-	latestTag, err := ghActionIface.GetGithubLatestTag(actionConfig.VersionRange)
+	latestTag, err := ghActionIface.GetGithubLatestTag(actionConfig.VersionRange, actionConfig.TagFormat, latestTagReachableFrom(actionConfig))
 	if err != nil {
-		core.Error(err.Error())
-		Exit(1)
+		return actionConfig, err
 	}
 	actionConfig.CurrentTag = latestTag
 	core.Debug("Latest tag is " + latestTag)
-	if actionConfig.NextTag == "" {
-		core.Debug("Getting increment type from github event")
-		incr, err := ghActionIface.GetIncrementType(actionConfig.EventPath)
-		if err != nil {
-			core.Error(err.Error())
-			Exit(1)
+	return actionConfig, nil
+}
+
+// executeBump fills in the increment and the next tag of actionConfig from
+// its latest tag.
+func executeBump(ghActionIface utils.GithubActionIface, actionConfig ActionConfig, labels labelSource) (ActionConfig, error) {
+	core.Debug("Getting increment type")
+	incr, err := resolveIncrement(ghActionIface, actionConfig, labels)
+	if err != nil {
+		return actionConfig, err
+	}
+	core.Debug("Increment type is: " + incr)
+	actionConfig.Increment = incr
+	core.Debug("Getting next tag from latest tag and increment type")
+	currentTag := actionConfig.CurrentTag
+	if currentTag == "" {
+		// the first release of a component
+		currentTag = BootstrapVersion
+	}
+	nextTag, err := ghActionIface.GetNextTag(currentTag, actionConfig.Increment, actionConfig.TagFormat, actionConfig.Prerelease)
+	if err != nil {
+		return actionConfig, err
+	}
+	nextTag = formatContext(actionConfig).Expand(nextTag)
+	core.Debug("Next tag is " + nextTag)
+	actionConfig.NextTag = nextTag
+	return actionConfig, nil
+}
+
+// latestTagReachableFrom returns the commit the latest tag has to be
+// reachable from, or an empty string when any tag will do.
+func latestTagReachableFrom(actionConfig ActionConfig) string {
+	if !actionConfig.ReachableTagsOnly {
+		return ""
+	}
+	return actionConfig.CustomReleaseSHA
+}
+
+// formatContext returns the values used to fill the non-version tag format
+// placeholders such as %sha% and %run_number%.
+func formatContext(actionConfig ActionConfig) semver.FormatContext {
+	return semver.FormatContext{
+		SHA:       actionConfig.CustomReleaseSHA,
+		RunNumber: actionConfig.RunNumber,
+		Date:      time.Now().UTC(),
+	}
+}
+
+// retryDelay is waited before the first retry, every further retry waits
+// one more retryDelay.
+var retryDelay = time.Second
+
+// executeRelease computes the next tag and creates its tag or release. When
+// the tag was created by a concurrent run in the meantime, the latest tag is
+// read again and the next tag recomputed, up to MaxRetries times, so no bump
+// is lost. Dry runs and skipped releases only compute the next tag. The
+// created or existing release is returned, nil for the other release
+// strategies.
+func executeRelease(ghActionIface utils.GithubActionIface, actionConfig ActionConfig, labels labelSource, isSkipRelease bool) (ActionConfig, *github.RepositoryRelease, error) {
+	ghActionIface = newPullRequestCache(ghActionIface)
+	var released ActionConfig
+	var release *github.RepositoryRelease
+	err := retryOnTagExists(actionConfig, func() error {
+		var err error
+		core.Debug("Executing next tag calculation now")
+		released, err = executeNextTag(ghActionIface, actionConfig, labels)
+		if err != nil || actionConfig.DryRun || isSkipRelease {
+			return err
 		}
-		core.Debug("Increment type is: " + string(incr))
-		actionConfig.Increment = string(incr)
-		core.Debug("Getting next tag from latest tag and increment type")
-		nextTag, err := ghActionIface.GetNextTag(latestTag, actionConfig.Increment, actionConfig.TagFormat)
+		var options utils.ReleaseOptions
+		released, options, err = executeReleaseContent(ghActionIface, released)
 		if err != nil {
+			return err
+		}
+		core.Debug("Executing release creation now")
+		release, err = executeCreateRelease(ghActionIface, released.CustomReleaseSHA, released.CurrentTag, released.NextTag, released.ReleaseStrategy, options)
+		if actionConfig.NextTag != "" {
+			return err
+		}
+		return concurrentTagConflict(ghActionIface, released, err)
+	})
+	if err != nil || actionConfig.DryRun || isSkipRelease {
+		return released, nil, err
+	}
+	if err := executeAssets(ghActionIface, released, release); err != nil {
+		return released, release, err
+	}
+	return released, release, executeFloatingTags(ghActionIface, released)
+}
+
+// concurrentTagConflict turns a TagConflictError of the computed next tag
+// into utils.ErrTagExists, so it is retried, when a concurrent run released
+// since the latest tag was read. A tag that existed before, e.g. outside the
+// version range, is reported as the TagConflictError.
+func concurrentTagConflict(ghActionIface utils.GithubActionIface, actionConfig ActionConfig, err error) error {
+	var conflict *TagConflictError
+	if !errors.As(err, &conflict) {
+		return err
+	}
+	latestTag, latestErr := ghActionIface.GetGithubLatestTag(actionConfig.VersionRange, actionConfig.TagFormat, latestTagReachableFrom(actionConfig))
+	if latestErr != nil || latestTag == actionConfig.CurrentTag {
+		return err
+	}
+	return fmt.Errorf("%w: %s was released concurrently: %w", utils.ErrTagExists, latestTag, err)
+}
+
+// retryOnTagExists runs release again while it fails with
+// utils.ErrTagExists. A next tag given as input is never retried, it cannot
+// change.
+func retryOnTagExists(actionConfig ActionConfig, release func() error) error {
+	for retry := 1; ; retry++ {
+		err := release()
+		if !errors.Is(err, utils.ErrTagExists) || actionConfig.NextTag != "" || retry > actionConfig.MaxRetries {
+			return err
+		}
+		core.Warningf("%s, retrying with the latest tag (%d/%d)", err, retry, actionConfig.MaxRetries)
+		time.Sleep(time.Duration(retry) * retryDelay)
+	}
+}
+
+func executeAction(ghActionIface utils.GithubActionIface, actionConfig ActionConfig) {
+	var labels labelSource
+	var isSkipRelease bool
+	switch actionConfig.EventName {
+	case EventPush:
+		labels, isSkipRelease = executePushEventGuard(ghActionIface, actionConfig)
+	case EventWorkflowDispatch:
+		labels, isSkipRelease = executeWorkflowDispatchGuard(actionConfig)
+	default:
+		labels, isSkipRelease = executePullRequestEventGuard(ghActionIface, actionConfig)
+	}
+
+	if len(actionConfig.Components) > 0 {
+		if err := executeComponentReleases(ghActionIface, actionConfig, labels, isSkipRelease); err != nil {
 			core.Error(err.Error())
 			Exit(1)
 		}
-		core.Debug("Next tag is " + nextTag)
-		actionConfig.NextTag = nextTag
+		return
 	}
 
-	if !isSkipRelease {
-		core.Debug("Executing release creation now")
-		err = executeCreateRelease(ghActionIface, actionConfig.CustomReleaseSHA, actionConfig.CurrentTag, actionConfig.NextTag, actionConfig.ReleaseStrategy)
-		if err != nil {
+	actionConfig, release, err := executeRelease(ghActionIface, actionConfig, labels, isSkipRelease)
+	if errors.Is(err, semver.ErrNoReleasableCommits) {
+		core.Info(err.Error())
+		Exit(0)
+	}
+	if err != nil {
+		core.Error(err.Error())
+		Exit(1)
+	}
+
+	if actionConfig.DryRun {
+		if err := executeDryRun(actionConfig, isSkipRelease); err != nil {
 			core.Error(err.Error())
 			Exit(1)
 		}
-	} else {
+		return
+	}
+
+	if isSkipRelease {
 		core.Info("Skipping release creation because of skip-release label")
 	}
-	core.SetOutput("tag", actionConfig.NextTag)
-	core.SetOutput("increment", actionConfig.Increment)
+	released := !isSkipRelease && actionConfig.ReleaseStrategy != ReleaseStrategyNone
+	newReleaseOutputs(actionConfig, release, released).set()
 	core.Infof("Release strategy was: %v, tag was: %v and next tag created was: %v, increment was: %v\n", actionConfig.ReleaseStrategy, actionConfig.CurrentTag, actionConfig.NextTag, actionConfig.Increment)
 }
+
+// newBackend creates the implementation the action talks to the repository
+// through: the GitHub API or a local git checkout.
+var newBackend = func(actionConfig ActionConfig) (utils.GithubActionIface, error) {
+	switch actionConfig.Backend {
+	case "", BackendGithub:
+		return utils.NewGithubActionImpl(actionConfig.GithubRepository, actionConfig.GithubToken, actionConfig.GithubApiUrl, actionConfig.GithubUploadsUrl)
+	case BackendGit:
+		gitDir := actionConfig.GitDir
+		if gitDir == "" {
+			gitDir = "."
+		}
+		remote := actionConfig.GitRemote
+		if remote == GitRemoteNone {
+			remote = ""
+		}
+		impl, err := utils.NewGitActionImpl(gitDir, remote)
+		if err != nil {
+			return nil, err
+		}
+		impl.SigningFormat = actionConfig.SigningFormat
+		impl.SigningKey = actionConfig.SigningKey
+		return impl, nil
+	}
+	return nil, fmt.Errorf("invalid backend: %s", actionConfig.Backend)
+}
+
 func main() {
-	actionConfig := ActionConfigFromEnv()
-	ghIface, err := utils.NewGithubActionImpl(actionConfig.GithubRepository, actionConfig.GithubToken, actionConfig.GithubApiUrl, actionConfig.GithubUploadsUrl)
+	if len(os.Args) > 1 {
+		os.Exit(runCLI(os.Args[1:], os.Stdout, os.Stderr))
+	}
+
+	actionConfig, err := LoadActionConfig()
+	if err != nil {
+		core.Error(err.Error())
+		os.Exit(1)
+	}
+
+	ghIface, err := newBackend(actionConfig)
 	if err != nil {
 		core.Error(err.Error())
 		os.Exit(1)
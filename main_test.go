@@ -2,12 +2,16 @@ package main
 
 import (
 	"errors"
+	"fmt"
+	"os/exec"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	github "github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
 	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExecuteCreateRelease(t *testing.T) {
@@ -19,6 +23,7 @@ func TestExecuteCreateRelease(t *testing.T) {
 	tests := []struct {
 		name            string
 		releaseStrategy string
+		options         utils.ReleaseOptions
 		setupMock       func()
 		expectedError   error
 	}{
@@ -32,20 +37,30 @@ func TestExecuteCreateRelease(t *testing.T) {
 			name:            "Successful Release strategy Release",
 			releaseStrategy: ReleaseStrategyRelease,
 			setupMock: func() {
-				// Expect a successful call to CreateGithubRelease
-				mockGHActionIface.EXPECT().CreateGithubRelease("v1.0.0", "abc123").Return(nil)
-
-				// Expect a successful call to GenerateReleaseNotes
-				mockGHActionIface.EXPECT().GenerateReleaseNotes("v1.0.0", "v0.0.1").Return(nil, nil, nil)
+				// the notes generated since the previous tag become the body
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().GenerateReleaseNotes("v1.0.0", "abc123", "v0.0.1").Return(&github.RepositoryReleaseNotes{Body: "**Full Changelog**: v0.0.1...v1.0.0"}, nil, nil)
+				mockGHActionIface.EXPECT().CreateGithubRelease("v1.0.0", "abc123", utils.ReleaseOptions{Body: "**Full Changelog**: v0.0.1...v1.0.0"}).Return(nil, nil)
 			},
 			expectedError: nil,
 		},
+		{
+			name:            "Failed generating release notes",
+			releaseStrategy: ReleaseStrategyRelease,
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().GenerateReleaseNotes("v1.0.0", "abc123", "v0.0.1").Return(nil, nil, errors.New("generating notes failed"))
+			},
+			expectedError: errors.New("generating notes failed"),
+		},
 		{
 			name:            "Failed Release strategy Release",
 			releaseStrategy: ReleaseStrategyRelease,
 			setupMock: func() {
 				// Expect CreateGithubRelease to return an error
-				mockGHActionIface.EXPECT().CreateGithubRelease("v1.0.0", "abc123").Return(errors.New("release creation failed"))
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().GenerateReleaseNotes("v1.0.0", "abc123", "v0.0.1").Return(&github.RepositoryReleaseNotes{}, nil, nil)
+				mockGHActionIface.EXPECT().CreateGithubRelease("v1.0.0", "abc123", utils.ReleaseOptions{}).Return(nil, errors.New("release creation failed"))
 			},
 			expectedError: errors.New("release creation failed"),
 		},
@@ -54,7 +69,8 @@ func TestExecuteCreateRelease(t *testing.T) {
 			releaseStrategy: ReleaseStrategyTag,
 			setupMock: func() {
 				// Expect a successful call to CreateGithubTag
-				mockGHActionIface.EXPECT().CreateGithubTag("v1.0.0", "abc123").Return(nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubTag("v1.0.0", "abc123", utils.TagOptions{}).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -63,10 +79,94 @@ func TestExecuteCreateRelease(t *testing.T) {
 			releaseStrategy: ReleaseStrategyTag,
 			setupMock: func() {
 				// Expect CreateGithubTag to return an error
-				mockGHActionIface.EXPECT().CreateGithubTag("v1.0.0", "abc123").Return(errors.New("tag creation failed"))
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubTag("v1.0.0", "abc123", utils.TagOptions{}).Return(errors.New("tag creation failed"))
 			},
 			expectedError: errors.New("tag creation failed"),
 		},
+		{
+			name:            "Release strategy Tag with existing tag",
+			releaseStrategy: ReleaseStrategyTag,
+			setupMock: func() {
+				// The tag already points to the SHA, e.g. on a re-run
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("abc123", nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "Release strategy Tag with conflicting tag",
+			releaseStrategy: ReleaseStrategyTag,
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("def456", nil)
+			},
+			expectedError: &TagConflictError{Tag: "v1.0.0", SHA: "abc123", ExistingSHA: "def456"},
+		},
+		{
+			name:            "Release strategy Release with existing release",
+			releaseStrategy: ReleaseStrategyRelease,
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("abc123", nil)
+				mockGHActionIface.EXPECT().ReleaseExists("v1.0.0").Return(true, nil)
+				mockGHActionIface.EXPECT().GetGithubRelease("v1.0.0").Return(&github.RepositoryRelease{ID: github.Int64(1)}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "Release strategy Release with existing tag only",
+			releaseStrategy: ReleaseStrategyRelease,
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("abc123", nil)
+				mockGHActionIface.EXPECT().ReleaseExists("v1.0.0").Return(false, nil)
+				mockGHActionIface.EXPECT().CreateGithubRelease("v1.0.0", "abc123", utils.ReleaseOptions{}).Return(nil, nil)
+				mockGHActionIface.EXPECT().GenerateReleaseNotes("v1.0.0", "abc123", "v0.0.1").Return(&github.RepositoryReleaseNotes{}, nil, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "Draft release creates its tag",
+			releaseStrategy: ReleaseStrategyRelease,
+			options:         utils.ReleaseOptions{Draft: true, MakeLatest: MakeLatestFalse},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubTag("v1.0.0", "abc123", utils.TagOptions{}).Return(nil)
+				mockGHActionIface.EXPECT().CreateGithubRelease("v1.0.0", "abc123", utils.ReleaseOptions{Draft: true, MakeLatest: MakeLatestFalse}).Return(nil, nil)
+				mockGHActionIface.EXPECT().GenerateReleaseNotes("v1.0.0", "abc123", "v0.0.1").Return(&github.RepositoryReleaseNotes{}, nil, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "Draft release re-run",
+			releaseStrategy: ReleaseStrategyRelease,
+			options:         utils.ReleaseOptions{Draft: true, MakeLatest: MakeLatestFalse},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("abc123", nil)
+				mockGHActionIface.EXPECT().ReleaseExists("v1.0.0").Return(true, nil)
+				mockGHActionIface.EXPECT().GetGithubRelease("v1.0.0").Return(&github.RepositoryRelease{ID: github.Int64(1), Draft: github.Bool(true)}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "Annotated release creates its tag",
+			releaseStrategy: ReleaseStrategyRelease,
+			options:         utils.ReleaseOptions{Tag: utils.TagOptions{Annotated: true, Message: "v1.0.0"}},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubTag("v1.0.0", "abc123", utils.TagOptions{Annotated: true, Message: "v1.0.0"}).Return(nil)
+				mockGHActionIface.EXPECT().CreateGithubRelease("v1.0.0", "abc123", utils.ReleaseOptions{Tag: utils.TagOptions{Annotated: true, Message: "v1.0.0"}}).Return(nil, nil)
+				mockGHActionIface.EXPECT().GenerateReleaseNotes("v1.0.0", "abc123", "v0.0.1").Return(&github.RepositoryReleaseNotes{}, nil, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "Release with rendered release notes",
+			releaseStrategy: ReleaseStrategyRelease,
+			options:         utils.ReleaseOptions{Body: "## What's Changed"},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubRelease("v1.0.0", "abc123", utils.ReleaseOptions{Body: "## What's Changed"}).Return(nil, nil)
+			},
+			expectedError: nil,
+		},
 		{
 			name:            "Invalid Release strategy",
 			releaseStrategy: "invalid",
@@ -78,12 +178,37 @@ func TestExecuteCreateRelease(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
-			err := executeCreateRelease(mockGHActionIface, "abc123", "v0.0.1", "v1.0.0", tt.releaseStrategy)
+			_, err := executeCreateRelease(mockGHActionIface, "abc123", "v0.0.1", "v1.0.0", tt.releaseStrategy, tt.options)
 			assert.Equal(t, tt.expectedError, err)
 		})
 	}
 }
 
+func TestReleaseOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		nextTag        string
+		markPrerelease string
+		expected       utils.ReleaseOptions
+	}{
+		{name: "Auto with pre-release", nextTag: "v1.1.0-rc.1", markPrerelease: MarkPrereleaseAuto, expected: utils.ReleaseOptions{Prerelease: true}},
+		{name: "Auto with release", nextTag: "v1.1.0", markPrerelease: MarkPrereleaseAuto, expected: utils.ReleaseOptions{}},
+		{name: "Empty is auto", nextTag: "v1.1.0-rc.1", expected: utils.ReleaseOptions{Prerelease: true}},
+		{name: "Always", nextTag: "v1.1.0", markPrerelease: MarkPrereleaseTrue, expected: utils.ReleaseOptions{Prerelease: true}},
+		{name: "Never", nextTag: "v1.1.0-rc.1", markPrerelease: MarkPrereleaseFalse, expected: utils.ReleaseOptions{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionConfig := ActionConfig{TagFormat: semver.DefaultTagFormat, NextTag: tt.nextTag, MarkPrerelease: tt.markPrerelease}
+			assert.Equal(t, tt.expected, releaseOptions(actionConfig))
+		})
+	}
+
+	actionConfig := ActionConfig{TagFormat: semver.DefaultTagFormat, NextTag: "v1.1.0", Draft: true, MakeLatest: MakeLatestLegacy}
+	assert.Equal(t, utils.ReleaseOptions{Draft: true, MakeLatest: MakeLatestLegacy}, releaseOptions(actionConfig))
+}
+
 func TestExecuteGuard(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -91,11 +216,12 @@ func TestExecuteGuard(t *testing.T) {
 	mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
 
 	tests := []struct {
-		name          string
-		releaseBranch string
-		eventPath     string
-		setupMock     func()
-		expectedError error
+		name            string
+		releaseBranch   string
+		eventPath       string
+		incrementSource string
+		setupMock       func()
+		expectedError   error
 	}{
 		{
 			name:          "empty releaseBranch or eventPath",
@@ -169,7 +295,7 @@ func TestExecuteGuard(t *testing.T) {
 					Action:      github.String("closed"),
 					PullRequest: &github.PullRequest{Merged: github.Bool(true), Base: &github.PullRequestBranch{Ref: github.String("main")}},
 				}, nil)
-				mockGHActionIface.EXPECT().GetIncrementType(gomock.Any()).Return("", ErrNoValidSemVerLabelFound)
+				mockGHActionIface.EXPECT().GetIncrementType(gomock.Any(), gomock.Any()).Return(semver.LabelResolution{}, ErrNoValidSemVerLabelFound)
 			},
 			expectedError: ErrNoValidSemVerLabelFound,
 		},
@@ -182,7 +308,20 @@ func TestExecuteGuard(t *testing.T) {
 					Action:      github.String("closed"),
 					PullRequest: &github.PullRequest{Merged: github.Bool(true), Base: &github.PullRequestBranch{Ref: github.String("main")}},
 				}, nil)
-				mockGHActionIface.EXPECT().GetIncrementType(gomock.Any()).Return("patch", nil)
+				mockGHActionIface.EXPECT().GetIncrementType(gomock.Any(), gomock.Any()).Return(semver.LabelResolution{Increment: semver.IncrementPatch}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:            "Labels not required for commits increment source",
+			releaseBranch:   "main",
+			eventPath:       "test_event.json",
+			incrementSource: IncrementSourceCommits,
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubEvent("test_event.json").Return(&github.PullRequestEvent{
+					Action:      github.String("closed"),
+					PullRequest: &github.PullRequest{Merged: github.Bool(true), Base: &github.PullRequestBranch{Ref: github.String("main")}},
+				}, nil)
 			},
 			expectedError: nil,
 		},
@@ -191,7 +330,7 @@ func TestExecuteGuard(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
-			err := executeGuard(mockGHActionIface, tt.releaseBranch, tt.eventPath)
+			err := executeGuard(mockGHActionIface, tt.releaseBranch, tt.eventPath, tt.incrementSource, semver.LabelMapping{})
 			assert.Equal(t, tt.expectedError, err)
 		})
 	}
@@ -239,15 +378,44 @@ func TestExecuteAction(t *testing.T) {
 					Action:      github.String("closed"),
 					PullRequest: &github.PullRequest{Merged: github.Bool(true), Base: &github.PullRequestBranch{Ref: github.String("main")}},
 				}, nil)
-				mockGHActionIface.EXPECT().GetIncrementType("test_event.json").Return("patch", nil)
-				mockGHActionIface.EXPECT().GetIncrementType("test_event.json").Return("patch", nil)
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{Increment: semver.IncrementPatch}, nil)
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{Increment: semver.IncrementPatch}, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skip-release", gomock.Any()).Return(false, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skipRelease", gomock.Any()).Return(false, nil)
-				mockGHActionIface.EXPECT().GetNextTag(gomock.Any(), gomock.Any(), gomock.Any()).Return("v1.0.1", nil)
-				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any()).Return("v1.0.0", nil)
-				mockGHActionIface.EXPECT().CreateGithubRelease("v1.0.1", "abc123").Return(nil)
-				// Expect a successful call to GenerateReleaseNotes
-				mockGHActionIface.EXPECT().GenerateReleaseNotes("v1.0.1", "v1.0.0").Return(nil, nil, nil)
+				mockGHActionIface.EXPECT().GetNextTag(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("v1.0.1", nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any(), gomock.Any(), "").Return("v1.0.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("def456", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.1").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubRelease("v1.0.1", "abc123", utils.ReleaseOptions{}).Return(nil, nil)
+				mockGHActionIface.EXPECT().GenerateReleaseNotes("v1.0.1", "abc123", "v1.0.0").Return(&github.RepositoryReleaseNotes{}, nil, nil)
+			},
+			expectedExit: 0,
+		},
+		{
+			name: "Re-run completes the release of the latest tag",
+			actionConfig: ActionConfig{
+				ReleaseBranch:    "main",
+				EventPath:        "test_event.json",
+				ReleaseStrategy:  ReleaseStrategyRelease,
+				TagFormat:        semver.DefaultTagFormat,
+				VersionRange:     ">0.0.0",
+				CustomReleaseSHA: "abc123",
+			},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubEvent("test_event.json").Return(&github.PullRequestEvent{
+					Action:      github.String("closed"),
+					PullRequest: &github.PullRequest{Merged: github.Bool(true), Base: &github.PullRequestBranch{Ref: github.String("main")}},
+				}, nil)
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{Increment: semver.IncrementPatch}, nil)
+				mockGHActionIface.EXPECT().DoesLabelExist("skip-release", gomock.Any()).Return(false, nil)
+				mockGHActionIface.EXPECT().DoesLabelExist("skipRelease", gomock.Any()).Return(false, nil)
+				// The previous run created v1.0.1 at the release SHA and failed afterwards
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", semver.DefaultTagFormat, "").Return("v1.0.1", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.1").Return("abc123", nil).Times(2)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0 <1.0.1", semver.DefaultTagFormat, "").Return("v1.0.0", nil)
+				mockGHActionIface.EXPECT().ReleaseExists("v1.0.1").Return(false, nil)
+				mockGHActionIface.EXPECT().CreateGithubRelease("v1.0.1", "abc123", utils.ReleaseOptions{}).Return(nil, nil)
+				mockGHActionIface.EXPECT().GenerateReleaseNotes("v1.0.1", "abc123", "v1.0.0").Return(&github.RepositoryReleaseNotes{}, nil, nil)
 			},
 			expectedExit: 0,
 		},
@@ -265,14 +433,15 @@ func TestExecuteAction(t *testing.T) {
 					Action:      github.String("closed"),
 					PullRequest: &github.PullRequest{Merged: github.Bool(true), Base: &github.PullRequestBranch{Ref: github.String("main")}},
 				}, nil)
-				mockGHActionIface.EXPECT().GetIncrementType("test_event.json").Return("patch", nil)
-				mockGHActionIface.EXPECT().GetIncrementType("test_event.json").Return("patch", nil)
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{Increment: semver.IncrementPatch}, nil)
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{Increment: semver.IncrementPatch}, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skip-release", gomock.Any()).Return(false, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skipRelease", gomock.Any()).Return(true, nil)
-				mockGHActionIface.EXPECT().GetNextTag(gomock.Any(), gomock.Any(), gomock.Any()).Return("v1.0.1", nil)
-				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any()).Return("v1.0.0", nil)
-				mockGHActionIface.EXPECT().CreateGithubRelease(gomock.Any(), gomock.Any()).Times(0)
-				mockGHActionIface.EXPECT().GenerateReleaseNotes(gomock.Any(), gomock.Any()).Times(0)
+				mockGHActionIface.EXPECT().GetNextTag(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("v1.0.1", nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any(), gomock.Any(), "").Return("v1.0.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("def456", nil)
+				mockGHActionIface.EXPECT().CreateGithubRelease(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				mockGHActionIface.EXPECT().GenerateReleaseNotes(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
 			expectedExit: 0,
 		},
@@ -341,11 +510,11 @@ func TestExecuteAction(t *testing.T) {
 				}, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skip-release", gomock.Any()).Return(false, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skipRelease", gomock.Any()).Return(false, nil)
-				mockGHActionIface.EXPECT().GetIncrementType("test_event.json").Return("patch", nil)
-				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any()).Return("v1.0.0", nil)
-				mockGHActionIface.EXPECT().CreateGithubRelease("v1.0.1", "abc123").Return(nil)
-				// Expect a successful call to GenerateReleaseNotes
-				mockGHActionIface.EXPECT().GenerateReleaseNotes("v1.0.1", gomock.Any()).Return(nil, nil, nil)
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{Increment: semver.IncrementPatch}, nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any(), gomock.Any(), "").Return("v1.0.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.1").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubRelease("v1.0.1", "abc123", utils.ReleaseOptions{}).Return(nil, nil)
+				mockGHActionIface.EXPECT().GenerateReleaseNotes("v1.0.1", "abc123", gomock.Any()).Return(&github.RepositoryReleaseNotes{}, nil, nil)
 
 			},
 			expectedExit: 0,
@@ -365,9 +534,9 @@ func TestExecuteAction(t *testing.T) {
 					PullRequest: &github.PullRequest{Merged: github.Bool(true), Base: &github.PullRequestBranch{Ref: github.String("main")}},
 				}, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skip-release", gomock.Any()).Return(true, nil)
-				mockGHActionIface.EXPECT().GetIncrementType("test_event.json").Return("patch", nil)
-				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any()).Return("v1.0.0", nil)
-				mockGHActionIface.EXPECT().CreateGithubRelease(gomock.Any(), gomock.Any()).Times(0)
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{Increment: semver.IncrementPatch}, nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any(), gomock.Any(), "").Return("v1.0.0", nil)
+				mockGHActionIface.EXPECT().CreateGithubRelease(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 			},
 			expectedExit: 0,
@@ -388,10 +557,10 @@ func TestExecuteAction(t *testing.T) {
 					Action:      github.String("closed"),
 					PullRequest: &github.PullRequest{Merged: github.Bool(true), Base: &github.PullRequestBranch{Ref: github.String("main")}},
 				}, nil)
-				mockGHActionIface.EXPECT().GetIncrementType("test_event.json").Return("patch", nil)
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{Increment: semver.IncrementPatch}, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skip-release", gomock.Any()).Return(false, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skipRelease", gomock.Any()).Return(false, nil)
-				mockGHActionIface.EXPECT().GetGithubLatestTag(">=1.0.0").Return("", errors.New("failed to get latest tag"))
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">=1.0.0", "v%d.%d.%d", "").Return("", errors.New("failed to get latest tag"))
 			},
 			expectedExit:  1,
 			expectedError: "failed to get latest tag",
@@ -418,7 +587,7 @@ func TestExecuteAction(t *testing.T) {
 				}, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skip-release", gomock.Any()).Return(false, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skipRelease", gomock.Any()).Return(false, nil)
-				mockGHActionIface.EXPECT().GetIncrementType("test_event.json").Return("", errors.New("failed to get increment"))
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{}, errors.New("failed to get increment"))
 			},
 			expectedExit:  1,
 			expectedError: "failed to get increment",
@@ -441,10 +610,11 @@ func TestExecuteAction(t *testing.T) {
 				}, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skip-release", gomock.Any()).Return(false, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skipRelease", gomock.Any()).Return(false, nil)
-				mockGHActionIface.EXPECT().GetIncrementType("test_event.json").Return("minor", nil)
-				mockGHActionIface.EXPECT().GetIncrementType("test_event.json").Return("minor", nil)
-				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any()).Return("v1.0.0", nil)
-				mockGHActionIface.EXPECT().GetNextTag("v1.0.0", "minor", "v%d.%d.%d").Return("", errors.New("failed to generate next tag"))
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{Increment: semver.IncrementMinor}, nil)
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{Increment: semver.IncrementMinor}, nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any(), gomock.Any(), "").Return("v1.0.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("def456", nil)
+				mockGHActionIface.EXPECT().GetNextTag("v1.0.0", "minor", "v%d.%d.%d", "").Return("", errors.New("failed to generate next tag"))
 			},
 			expectedExit:  1,
 			expectedError: "failed to generate next tag",
@@ -465,13 +635,267 @@ func TestExecuteAction(t *testing.T) {
 				}, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skip-release", gomock.Any()).Return(false, nil)
 				mockGHActionIface.EXPECT().DoesLabelExist("skipRelease", gomock.Any()).Return(false, nil)
-				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any()).Return("v1.0.0", nil)
-				mockGHActionIface.EXPECT().GetIncrementType("test_event.json").Return("minor", nil)
-				mockGHActionIface.EXPECT().CreateGithubRelease("v1.1.0", "abc123").Return(errors.New("failed to create release"))
+				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any(), gomock.Any(), "").Return("v1.0.0", nil)
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{Increment: semver.IncrementMinor}, nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.1.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().GenerateReleaseNotes("v1.1.0", "abc123", "v1.0.0").Return(&github.RepositoryReleaseNotes{}, nil, nil)
+				mockGHActionIface.EXPECT().CreateGithubRelease("v1.1.0", "abc123", utils.ReleaseOptions{}).Return(nil, errors.New("failed to create release"))
 			},
 			expectedExit:  1,
 			expectedError: "failed to create release",
 		},
+		{
+			name: "Successful execution with label mapping",
+			actionConfig: ActionConfig{
+				ReleaseBranch:    "main",
+				EventPath:        "test_event.json",
+				ReleaseStrategy:  ReleaseStrategyTag,
+				TagFormat:        "v%major%.%minor%.%patch%",
+				VersionRange:     ">0.0.0",
+				CustomReleaseSHA: "abc123",
+				LabelMapping:     semver.LabelMapping{Minor: []string{"semver:feature"}, Skip: []string{"no-release"}},
+			},
+			setupMock: func() {
+				mapping := semver.LabelMapping{Minor: []string{"semver:feature"}, Skip: []string{"no-release"}}
+				mockGHActionIface.EXPECT().ParseGithubEvent("test_event.json").Return(&github.PullRequestEvent{
+					Action:      github.String("closed"),
+					PullRequest: &github.PullRequest{Merged: github.Bool(true), Base: &github.PullRequestBranch{Ref: github.String("main")}},
+				}, nil)
+				mockGHActionIface.EXPECT().DoesLabelExist("no-release", "test_event.json").Return(false, nil)
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", mapping).Return(semver.LabelResolution{Increment: semver.IncrementMinor}, nil).Times(2)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", "v%major%.%minor%.%patch%", "").Return("v1.4.2", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.4.2").Return("def456", nil)
+				mockGHActionIface.EXPECT().GetNextTag("v1.4.2", "minor", "v%major%.%minor%.%patch%", "").Return("v1.5.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.5.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubTag("v1.5.0", "abc123", utils.TagOptions{}).Return(nil)
+			},
+			expectedExit: 0,
+		},
+		{
+			name: "Successful execution with reachable tags only",
+			actionConfig: ActionConfig{
+				ReleaseBranch:     "release/1.x",
+				EventPath:         "test_event.json",
+				ReleaseStrategy:   ReleaseStrategyTag,
+				TagFormat:         "v%major%.%minor%.%patch%",
+				VersionRange:      ">0.0.0",
+				CustomReleaseSHA:  "abc123",
+				ReachableTagsOnly: true,
+			},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubEvent("test_event.json").Return(&github.PullRequestEvent{
+					Action:      github.String("closed"),
+					PullRequest: &github.PullRequest{Merged: github.Bool(true), Base: &github.PullRequestBranch{Ref: github.String("release/1.x")}},
+				}, nil)
+				mockGHActionIface.EXPECT().DoesLabelExist("skip-release", gomock.Any()).Return(false, nil)
+				mockGHActionIface.EXPECT().DoesLabelExist("skipRelease", gomock.Any()).Return(false, nil)
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{Increment: semver.IncrementPatch}, nil).Times(2)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", "v%major%.%minor%.%patch%", "abc123").Return("v1.4.2", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.4.2").Return("def456", nil)
+				mockGHActionIface.EXPECT().GetNextTag("v1.4.2", "patch", "v%major%.%minor%.%patch%", "").Return("v1.4.3", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.4.3").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubTag("v1.4.3", "abc123", utils.TagOptions{}).Return(nil)
+			},
+			expectedExit: 0,
+		},
+		{
+			name: "Successful execution with commits increment source",
+			actionConfig: ActionConfig{
+				ReleaseBranch:    "main",
+				EventPath:        "test_event.json",
+				ReleaseStrategy:  ReleaseStrategyTag,
+				TagFormat:        "v%major%.%minor%.%patch%",
+				CustomReleaseSHA: "abc123",
+				IncrementSource:  IncrementSourceCommits,
+			},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubEvent("test_event.json").Return(&github.PullRequestEvent{
+					Action:      github.String("closed"),
+					PullRequest: &github.PullRequest{Merged: github.Bool(true), Base: &github.PullRequestBranch{Ref: github.String("main")}},
+				}, nil)
+				mockGHActionIface.EXPECT().DoesLabelExist("skip-release", gomock.Any()).Return(false, nil)
+				mockGHActionIface.EXPECT().DoesLabelExist("skipRelease", gomock.Any()).Return(false, nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any(), gomock.Any(), "").Return("v1.0.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("def456", nil)
+				mockGHActionIface.EXPECT().ListCommits("v1.0.0", "abc123").Return([]*github.RepositoryCommit{
+					{Commit: &github.Commit{Message: github.String("fix: handle empty input")}},
+					{Commit: &github.Commit{Message: github.String("feat(api): add endpoint")}},
+				}, nil)
+				mockGHActionIface.EXPECT().GetNextTag("v1.0.0", "minor", "v%major%.%minor%.%patch%", "").Return("v1.1.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.1.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubTag("v1.1.0", "abc123", utils.TagOptions{}).Return(nil)
+			},
+			expectedExit: 0,
+		},
+		{
+			name: "Both increment sources use the larger increment",
+			actionConfig: ActionConfig{
+				ReleaseBranch:    "main",
+				EventPath:        "test_event.json",
+				ReleaseStrategy:  ReleaseStrategyTag,
+				TagFormat:        "v%major%.%minor%.%patch%",
+				CustomReleaseSHA: "abc123",
+				IncrementSource:  IncrementSourceBoth,
+			},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubEvent("test_event.json").Return(&github.PullRequestEvent{
+					Action:      github.String("closed"),
+					PullRequest: &github.PullRequest{Merged: github.Bool(true), Base: &github.PullRequestBranch{Ref: github.String("main")}},
+				}, nil)
+				mockGHActionIface.EXPECT().DoesLabelExist("skip-release", gomock.Any()).Return(false, nil)
+				mockGHActionIface.EXPECT().DoesLabelExist("skipRelease", gomock.Any()).Return(false, nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any(), gomock.Any(), "").Return("v1.0.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("def456", nil)
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{Increment: semver.IncrementPatch}, nil)
+				mockGHActionIface.EXPECT().ListCommits("v1.0.0", "abc123").Return([]*github.RepositoryCommit{
+					{Commit: &github.Commit{Message: github.String("refactor!: drop v1 api")}},
+				}, nil)
+				mockGHActionIface.EXPECT().GetNextTag("v1.0.0", "major", "v%major%.%minor%.%patch%", "").Return("v2.0.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v2.0.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubTag("v2.0.0", "abc123", utils.TagOptions{}).Return(nil)
+			},
+			expectedExit: 0,
+		},
+		{
+			name: "No releasable commits skips the release",
+			actionConfig: ActionConfig{
+				ReleaseBranch:    "main",
+				EventPath:        "test_event.json",
+				ReleaseStrategy:  ReleaseStrategyTag,
+				CustomReleaseSHA: "abc123",
+				IncrementSource:  IncrementSourceCommits,
+			},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubEvent("test_event.json").Return(&github.PullRequestEvent{
+					Action:      github.String("closed"),
+					PullRequest: &github.PullRequest{Merged: github.Bool(true), Base: &github.PullRequestBranch{Ref: github.String("main")}},
+				}, nil)
+				mockGHActionIface.EXPECT().DoesLabelExist("skip-release", gomock.Any()).Return(false, nil)
+				mockGHActionIface.EXPECT().DoesLabelExist("skipRelease", gomock.Any()).Return(false, nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any(), gomock.Any(), "").Return("v1.0.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("def456", nil)
+				mockGHActionIface.EXPECT().ListCommits("v1.0.0", "abc123").Return([]*github.RepositoryCommit{
+					{Commit: &github.Commit{Message: github.String("chore: bump deps")}},
+				}, nil)
+				mockGHActionIface.EXPECT().CreateGithubTag(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedExit: 0,
+		},
+		{
+			name: "Push event uses labels of the merged pull request",
+			actionConfig: ActionConfig{
+				ReleaseBranch:    "main",
+				EventPath:        "test_event.json",
+				EventName:        EventPush,
+				ReleaseStrategy:  ReleaseStrategyTag,
+				TagFormat:        "v%major%.%minor%.%patch%",
+				CustomReleaseSHA: "abc123",
+			},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubPushEvent("test_event.json").Return(&github.PushEvent{
+					Ref:   github.String("refs/heads/main"),
+					After: github.String("abc123"),
+				}, nil)
+				mockGHActionIface.EXPECT().ListPullRequestsWithCommit("abc123").Return([]*github.PullRequest{{
+					MergedAt: &github.Timestamp{},
+					Base:     &github.PullRequestBranch{Ref: github.String("main")},
+					Labels:   []*github.Label{{Name: github.String("minor")}},
+				}}, nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any(), gomock.Any(), "").Return("v1.0.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("def456", nil)
+				mockGHActionIface.EXPECT().GetNextTag("v1.0.0", "minor", "v%major%.%minor%.%patch%", "").Return("v1.1.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.1.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubTag("v1.1.0", "abc123", utils.TagOptions{}).Return(nil)
+			},
+			expectedExit: 0,
+		},
+		{
+			name: "Direct push falls back to commits",
+			actionConfig: ActionConfig{
+				ReleaseBranch:    "main",
+				EventPath:        "test_event.json",
+				EventName:        EventPush,
+				ReleaseStrategy:  ReleaseStrategyTag,
+				TagFormat:        "v%major%.%minor%.%patch%",
+				CustomReleaseSHA: "abc123",
+			},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().ParseGithubPushEvent("test_event.json").Return(&github.PushEvent{
+					Ref:   github.String("refs/heads/main"),
+					After: github.String("abc123"),
+				}, nil)
+				mockGHActionIface.EXPECT().ListPullRequestsWithCommit("abc123").Return(nil, nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any(), gomock.Any(), "").Return("v1.0.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("def456", nil)
+				mockGHActionIface.EXPECT().ListCommits("v1.0.0", "abc123").Return([]*github.RepositoryCommit{
+					{Commit: &github.Commit{Message: github.String("fix: handle empty input")}},
+				}, nil)
+				mockGHActionIface.EXPECT().GetNextTag("v1.0.0", "patch", "v%major%.%minor%.%patch%", "").Return("v1.0.1", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.1").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubTag("v1.0.1", "abc123", utils.TagOptions{}).Return(nil)
+			},
+			expectedExit: 0,
+		},
+		{
+			name: "Manual release with explicit increment",
+			actionConfig: ActionConfig{
+				EventName:        EventWorkflowDispatch,
+				ReleaseBranch:    "main",
+				GithubRef:        "refs/heads/main",
+				Increment:        "major",
+				IncrementSource:  IncrementSourceCommits,
+				ReleaseStrategy:  ReleaseStrategyRelease,
+				TagFormat:        "v%major%.%minor%.%patch%",
+				CustomReleaseSHA: "abc123",
+			},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any(), gomock.Any(), "").Return("v1.0.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("def456", nil)
+				mockGHActionIface.EXPECT().GetNextTag("v1.0.0", "major", "v%major%.%minor%.%patch%", "").Return("v2.0.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v2.0.0").Return("", utils.ErrTagNotFound)
+				mockGHActionIface.EXPECT().CreateGithubRelease("v2.0.0", "abc123", utils.ReleaseOptions{}).Return(nil, nil)
+				mockGHActionIface.EXPECT().GenerateReleaseNotes("v2.0.0", "abc123", "v1.0.0").Return(&github.RepositoryReleaseNotes{}, nil, nil)
+			},
+			expectedExit: 0,
+		},
+		{
+			name: "Manual release with invalid increment",
+			actionConfig: ActionConfig{
+				EventName:        EventWorkflowDispatch,
+				ReleaseBranch:    "main",
+				GithubRef:        "refs/heads/main",
+				Increment:        "micro",
+				ReleaseStrategy:  ReleaseStrategyRelease,
+				CustomReleaseSHA: "abc123",
+			},
+			setupMock:    func() {},
+			expectedExit: 1,
+		},
+		{
+			name: "Dry run of an open pull request",
+			actionConfig: ActionConfig{
+				ReleaseBranch:    "main",
+				EventPath:        "test_event.json",
+				ReleaseStrategy:  ReleaseStrategyRelease,
+				CustomReleaseSHA: "abc123",
+				DryRun:           true,
+			},
+			setupMock: func() {
+				mockGHActionIface.EXPECT().DoesLabelExist("skip-release", gomock.Any()).Return(false, nil)
+				mockGHActionIface.EXPECT().DoesLabelExist("skipRelease", gomock.Any()).Return(false, nil)
+				mockGHActionIface.EXPECT().ParseGithubEvent("test_event.json").Return(&github.PullRequestEvent{
+					Action:      github.String("synchronize"),
+					PullRequest: &github.PullRequest{Merged: github.Bool(false), Base: &github.PullRequestBranch{Ref: github.String("main")}},
+				}, nil)
+				mockGHActionIface.EXPECT().GetGithubLatestTag(gomock.Any(), gomock.Any(), "").Return("v1.0.0", nil)
+				mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("def456", nil)
+				mockGHActionIface.EXPECT().GetIncrementType("test_event.json", semver.LabelMapping{}).Return(semver.LabelResolution{Increment: semver.IncrementMinor}, nil)
+				mockGHActionIface.EXPECT().GetNextTag("v1.0.0", "minor", gomock.Any(), "").Return("v1.1.0", nil)
+				mockGHActionIface.EXPECT().CreateGithubRelease(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				mockGHActionIface.EXPECT().GenerateReleaseNotes(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedExit: 0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -496,3 +920,206 @@ func TestExecuteAction(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteReleaseRetriesConcurrentTag(t *testing.T) {
+	retryDelay = 0
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+	actionConfig := ActionConfig{
+		ReleaseStrategy:  ReleaseStrategyTag,
+		TagFormat:        semver.DefaultTagFormat,
+		VersionRange:     ">0.0.0",
+		CustomReleaseSHA: "abc123",
+		MaxRetries:       1,
+	}
+
+	gomock.InOrder(
+		mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", semver.DefaultTagFormat, "").Return("v1.0.0", nil),
+		mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("aaa111", nil),
+		mockGHActionIface.EXPECT().GetNextTag("v1.0.0", "patch", semver.DefaultTagFormat, "").Return("v1.0.1", nil),
+		mockGHActionIface.EXPECT().GetTagSHA("v1.0.1").Return("", utils.ErrTagNotFound),
+		// a concurrent run created v1.0.1 between the check and the create
+		mockGHActionIface.EXPECT().CreateGithubTag("v1.0.1", "abc123", utils.TagOptions{}).Return(fmt.Errorf("%w: v1.0.1", utils.ErrTagExists)),
+		mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", semver.DefaultTagFormat, "").Return("v1.0.1", nil),
+		mockGHActionIface.EXPECT().GetTagSHA("v1.0.1").Return("def456", nil),
+		mockGHActionIface.EXPECT().GetNextTag("v1.0.1", "patch", semver.DefaultTagFormat, "").Return("v1.0.2", nil),
+		mockGHActionIface.EXPECT().GetTagSHA("v1.0.2").Return("", utils.ErrTagNotFound),
+		mockGHActionIface.EXPECT().CreateGithubTag("v1.0.2", "abc123", utils.TagOptions{}).Return(nil),
+	)
+
+	released, _, err := executeRelease(mockGHActionIface, actionConfig, manualIncrement{incr: "patch"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.1", released.CurrentTag)
+	assert.Equal(t, "v1.0.2", released.NextTag)
+}
+
+func TestExecuteReleaseExistingTagConflict(t *testing.T) {
+	retryDelay = 0
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+	actionConfig := ActionConfig{
+		ReleaseStrategy:  ReleaseStrategyTag,
+		TagFormat:        semver.DefaultTagFormat,
+		VersionRange:     ">0.0.0",
+		CustomReleaseSHA: "abc123",
+		MaxRetries:       3,
+	}
+
+	mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", semver.DefaultTagFormat, "").Return("v1.0.0", nil).Times(2)
+	mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("aaa111", nil)
+	mockGHActionIface.EXPECT().GetNextTag("v1.0.0", "patch", semver.DefaultTagFormat, "").Return("v1.0.1", nil)
+	// v1.0.1 existed before the run, the latest tag did not move
+	mockGHActionIface.EXPECT().GetTagSHA("v1.0.1").Return("def456", nil)
+
+	_, _, err := executeRelease(mockGHActionIface, actionConfig, manualIncrement{incr: "patch"}, false)
+	var conflict *TagConflictError
+	assert.ErrorAs(t, err, &conflict)
+	assert.NotErrorIs(t, err, utils.ErrTagExists)
+}
+
+func TestExecuteReleaseConcurrentTagConflict(t *testing.T) {
+	retryDelay = 0
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+	actionConfig := ActionConfig{
+		ReleaseStrategy:  ReleaseStrategyTag,
+		TagFormat:        semver.DefaultTagFormat,
+		VersionRange:     ">0.0.0",
+		CustomReleaseSHA: "abc123",
+		MaxRetries:       1,
+	}
+
+	gomock.InOrder(
+		mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", semver.DefaultTagFormat, "").Return("v1.0.0", nil),
+		mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("aaa111", nil),
+		mockGHActionIface.EXPECT().GetNextTag("v1.0.0", "patch", semver.DefaultTagFormat, "").Return("v1.0.1", nil),
+		// a concurrent run created v1.0.1 after the latest tag was read
+		mockGHActionIface.EXPECT().GetTagSHA("v1.0.1").Return("def456", nil),
+		mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", semver.DefaultTagFormat, "").Return("v1.0.1", nil),
+		mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", semver.DefaultTagFormat, "").Return("v1.0.1", nil),
+		mockGHActionIface.EXPECT().GetTagSHA("v1.0.1").Return("def456", nil),
+		mockGHActionIface.EXPECT().GetNextTag("v1.0.1", "patch", semver.DefaultTagFormat, "").Return("v1.0.2", nil),
+		mockGHActionIface.EXPECT().GetTagSHA("v1.0.2").Return("", utils.ErrTagNotFound),
+		mockGHActionIface.EXPECT().CreateGithubTag("v1.0.2", "abc123", utils.TagOptions{}).Return(nil),
+	)
+
+	released, _, err := executeRelease(mockGHActionIface, actionConfig, manualIncrement{incr: "patch"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.2", released.NextTag)
+}
+
+func TestExecuteReleaseReturnsRelease(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+	actionConfig := ActionConfig{
+		ReleaseStrategy:  ReleaseStrategyRelease,
+		TagFormat:        semver.DefaultTagFormat,
+		VersionRange:     ">0.0.0",
+		CustomReleaseSHA: "abc123",
+	}
+	created := &github.RepositoryRelease{ID: github.Int64(42)}
+
+	mockGHActionIface.EXPECT().GetGithubLatestTag(">0.0.0", semver.DefaultTagFormat, "").Return("v1.0.0", nil)
+	mockGHActionIface.EXPECT().GetTagSHA("v1.0.0").Return("aaa111", nil)
+	mockGHActionIface.EXPECT().GetNextTag("v1.0.0", "patch", semver.DefaultTagFormat, "").Return("v1.0.1", nil)
+	mockGHActionIface.EXPECT().GetTagSHA("v1.0.1").Return("", utils.ErrTagNotFound)
+	mockGHActionIface.EXPECT().CreateGithubRelease("v1.0.1", "abc123", utils.ReleaseOptions{}).Return(created, nil)
+	mockGHActionIface.EXPECT().GenerateReleaseNotes("v1.0.1", "abc123", "v1.0.0").Return(&github.RepositoryReleaseNotes{}, nil, nil)
+
+	released, release, err := executeRelease(mockGHActionIface, actionConfig, manualIncrement{incr: "patch"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.1", released.NextTag)
+	assert.Same(t, created, release)
+}
+
+func TestNewBackendGitRemote(t *testing.T) {
+	dir := t.TempDir()
+	out, err := exec.Command("git", "init", "--quiet", dir).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	actionConfig := defaultActionConfig()
+	actionConfig.Backend = BackendGit
+	actionConfig.GitDir = dir
+	backend, err := newBackend(actionConfig)
+	require.NoError(t, err)
+	assert.Equal(t, "origin", backend.(*utils.GitActionImpl).Remote)
+
+	actionConfig.GitRemote = GitRemoteNone
+	backend, err = newBackend(actionConfig)
+	require.NoError(t, err)
+	assert.Empty(t, backend.(*utils.GitActionImpl).Remote)
+}
+
+func TestExecuteAlreadyReleasedOrRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+	actionConfig := ActionConfig{
+		TagFormat:        semver.DefaultTagFormat,
+		VersionRange:     "<2.0.0 || >=3.0.0",
+		CurrentTag:       "v3.1.0",
+		CustomReleaseSHA: "abc123",
+	}
+
+	mockGHActionIface.EXPECT().GetTagSHA("v3.1.0").Return("abc123", nil)
+	mockGHActionIface.EXPECT().GetGithubLatestTag("<2.0.0 <3.1.0 || >=3.0.0 <3.1.0", semver.DefaultTagFormat, "").Return("v3.0.0", nil)
+
+	released, alreadyReleased, err := executeAlreadyReleased(mockGHActionIface, actionConfig)
+	require.NoError(t, err)
+	assert.True(t, alreadyReleased)
+	assert.Equal(t, "v3.0.0", released.CurrentTag)
+	assert.Equal(t, "v3.1.0", released.NextTag)
+}
+
+func TestResolveIncrementBoth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+	actionConfig := ActionConfig{
+		IncrementSource:  IncrementSourceBoth,
+		CurrentTag:       "v1.0.0",
+		CustomReleaseSHA: "abc123",
+	}
+	commits := []*github.RepositoryCommit{{Commit: &github.Commit{Message: github.String("fix: handle empty input")}}}
+	withLabels := func(names ...string) pullRequestLabels {
+		pullRequest := &github.PullRequest{}
+		for _, name := range names {
+			pullRequest.Labels = append(pullRequest.Labels, &github.Label{Name: github.String(name)})
+		}
+		return pullRequestLabels{pullRequest: pullRequest}
+	}
+
+	tests := []struct {
+		name          string
+		labels        labelSource
+		listsCommits  bool
+		expectedIncr  string
+		expectedError error
+	}{
+		{name: "Labels and commits", labels: withLabels("minor"), listsCommits: true, expectedIncr: "minor"},
+		{name: "No pull request", labels: pullRequestLabels{}, listsCommits: true, expectedIncr: "patch"},
+		{name: "No increment labels", labels: withLabels("docs"), listsCommits: true, expectedIncr: "patch"},
+		{name: "Conflicting labels", labels: withLabels("minor", "major"), expectedError: semver.ErrMultipleValidLabels},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.listsCommits {
+				mockGHActionIface.EXPECT().ListCommits("v1.0.0", "abc123").Return(commits, nil)
+			}
+			incr, err := resolveIncrement(mockGHActionIface, actionConfig, tt.labels)
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expectedIncr, incr)
+		})
+	}
+}
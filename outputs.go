@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/actions-go/toolkit/core"
+	"github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+)
+
+// releaseOutputs are the outputs of the action for a release, see
+// action.yml. The version parts are empty when the next tag does not parse,
+// the release ones without a GitHub release.
+type releaseOutputs struct {
+	Tag         string
+	PreviousTag string
+	Increment   string
+	Version     string
+	Major       string
+	Minor       string
+	Patch       string
+	Prerelease  string
+	ReleaseID   string
+	ReleaseURL  string
+	UploadURL   string
+	SHA         string
+	Released    bool
+}
+
+// newReleaseOutputs returns the outputs of the release of actionConfig's
+// next tag. released reports whether its tag or release was created, or
+// already existed from an earlier run.
+func newReleaseOutputs(actionConfig ActionConfig, release *github.RepositoryRelease, released bool) releaseOutputs {
+	outputs := releaseOutputs{
+		Tag:         actionConfig.NextTag,
+		PreviousTag: actionConfig.CurrentTag,
+		Increment:   actionConfig.Increment,
+		SHA:         actionConfig.CustomReleaseSHA,
+		Released:    released,
+	}
+	if version, err := semver.ParseTag(actionConfig.TagFormat, actionConfig.NextTag); err == nil {
+		outputs.Version = version.Format("%major%.%minor%.%patch%")
+		outputs.Major = strconv.FormatUint(version.Major(), 10)
+		outputs.Minor = strconv.FormatUint(version.Minor(), 10)
+		outputs.Patch = strconv.FormatUint(version.Patch(), 10)
+		outputs.Prerelease = version.Prerelease()
+	}
+	if release != nil {
+		outputs.ReleaseID = strconv.FormatInt(release.GetID(), 10)
+		outputs.ReleaseURL = release.GetHTMLURL()
+		outputs.UploadURL = release.GetUploadURL()
+	}
+	return outputs
+}
+
+func (outputs releaseOutputs) set() {
+	for name, value := range map[string]string{
+		"tag":          outputs.Tag,
+		"previous_tag": outputs.PreviousTag,
+		"increment":    outputs.Increment,
+		"version":      outputs.Version,
+		"major":        outputs.Major,
+		"minor":        outputs.Minor,
+		"patch":        outputs.Patch,
+		"prerelease":   outputs.Prerelease,
+		"release_id":   outputs.ReleaseID,
+		"release_url":  outputs.ReleaseURL,
+		"upload_url":   outputs.UploadURL,
+		"sha":          outputs.SHA,
+		"released":     strconv.FormatBool(outputs.Released),
+	} {
+		core.SetOutput(name, value)
+	}
+}
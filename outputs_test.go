@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	github "github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReleaseOutputs(t *testing.T) {
+	actionConfig := ActionConfig{
+		TagFormat:        semver.DefaultTagFormat,
+		CurrentTag:       "v1.2.3",
+		NextTag:          "v1.3.0-rc.1",
+		Increment:        "minor",
+		CustomReleaseSHA: "abc123",
+	}
+	release := &github.RepositoryRelease{
+		ID:        github.Int64(42),
+		HTMLURL:   github.String("https://github.com/owner/repo/releases/tag/v1.3.0-rc.1"),
+		UploadURL: github.String("https://uploads.github.com/repos/owner/repo/releases/42/assets{?name,label}"),
+	}
+
+	assert.Equal(t, releaseOutputs{
+		Tag:         "v1.3.0-rc.1",
+		PreviousTag: "v1.2.3",
+		Increment:   "minor",
+		Version:     "1.3.0-rc.1",
+		Major:       "1",
+		Minor:       "3",
+		Patch:       "0",
+		Prerelease:  "rc.1",
+		ReleaseID:   "42",
+		ReleaseURL:  "https://github.com/owner/repo/releases/tag/v1.3.0-rc.1",
+		UploadURL:   "https://uploads.github.com/repos/owner/repo/releases/42/assets{?name,label}",
+		SHA:         "abc123",
+		Released:    true,
+	}, newReleaseOutputs(actionConfig, release, true))
+
+	actionConfig.TagFormat = "app-%major%.%minor%.%patch%"
+	actionConfig.NextTag = "app-2.0.0"
+	assert.Equal(t, releaseOutputs{
+		Tag:         "app-2.0.0",
+		PreviousTag: "v1.2.3",
+		Increment:   "minor",
+		Version:     "2.0.0",
+		Major:       "2",
+		Minor:       "0",
+		Patch:       "0",
+		SHA:         "abc123",
+	}, newReleaseOutputs(actionConfig, nil, false))
+}
@@ -0,0 +1,60 @@
+package notes
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ChangelogTemplate renders the section of a release in a changelog in Keep
+// a Changelog format.
+const ChangelogTemplate = `## [{{ .Tag }}] - {{ .Date.Format "2006-01-02" }}
+{{- range .Sections }}
+
+### {{ .Title }}
+{{ range .Entries }}
+- {{ if .Breaking }}**Breaking:** {{ end }}{{ .Title }}{{ if .Number }} (#{{ .Number }}){{ else }} ({{ .ShortSHA }}){{ end }}
+{{- end }}
+{{- end }}
+`
+
+// DefaultChangelogSections group changelog entries into the Keep a
+// Changelog types of changes.
+var DefaultChangelogSections = []Section{
+	{Title: "Added", Labels: []string{"minor"}, Types: []string{"feat"}},
+	{Title: "Fixed", Labels: []string{"patch"}, Types: []string{"fix"}},
+	{Title: "Changed"},
+}
+
+// ChangelogHeader starts a new changelog.
+const ChangelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+var unreleasedHeading = regexp.MustCompile(`(?i)^## \[?unreleased\]?`)
+
+// HasChangelogSection reports whether changelog has a section of tag.
+func HasChangelogSection(changelog, tag string) bool {
+	heading := regexp.MustCompile(`(?m)^## \[?` + regexp.QuoteMeta(tag) + `\]?(\s|$)`)
+	return heading.MatchString(changelog)
+}
+
+// PrependChangelogSection inserts section above the latest release of
+// changelog, below its header and an [Unreleased] section. An empty
+// changelog gets ChangelogHeader.
+func PrependChangelogSection(changelog, section string) string {
+	if strings.TrimSpace(changelog) == "" {
+		changelog = ChangelogHeader
+	}
+	section = strings.TrimRight(section, "\n") + "\n"
+	lines := strings.SplitAfter(changelog, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") && !unreleasedHeading.MatchString(line) {
+			return strings.Join(lines[:i], "") + section + "\n" + strings.Join(lines[i:], "")
+		}
+	}
+	return strings.TrimRight(changelog, "\n") + "\n\n" + section
+}
@@ -0,0 +1,77 @@
+package notes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderChangelogSection(t *testing.T) {
+	data := testData(DefaultChangelogSections)
+	data.Date = time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	section, err := Render(ChangelogTemplate, data)
+	require.NoError(t, err)
+	assert.Equal(t, `## [v2.0.0] - 2024-10-01
+
+### Added
+
+- **Breaking:** Add flag (#12)
+
+### Fixed
+
+- Bump dependencies (#13)
+- fix: typo in readme (3333333)
+
+### Changed
+
+- chore: release tooling (5555555)
+`, section)
+}
+
+func TestPrependChangelogSection(t *testing.T) {
+	section := "## [v1.1.0] - 2024-10-01\n\n### Added\n\n- Add flag (#12)\n"
+
+	tests := []struct {
+		name      string
+		changelog string
+		expected  string
+	}{
+		{
+			name:      "New changelog",
+			changelog: "",
+			expected:  ChangelogHeader + "\n" + section,
+		},
+		{
+			name:      "Above the latest release",
+			changelog: "# Changelog\n\n## [v1.0.0] - 2024-09-01\n\n- Initial release\n",
+			expected:  "# Changelog\n\n" + section + "\n## [v1.0.0] - 2024-09-01\n\n- Initial release\n",
+		},
+		{
+			name:      "Below the unreleased section",
+			changelog: "# Changelog\n\n## [Unreleased]\n\n## [v1.0.0] - 2024-09-01\n",
+			expected:  "# Changelog\n\n## [Unreleased]\n\n" + section + "\n## [v1.0.0] - 2024-09-01\n",
+		},
+		{
+			name:      "Header only",
+			changelog: "# Changelog\n",
+			expected:  "# Changelog\n\n" + section,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, PrependChangelogSection(tt.changelog, section))
+		})
+	}
+}
+
+func TestHasChangelogSection(t *testing.T) {
+	changelog := "# Changelog\n\n## [v1.1.0] - 2024-10-01\n\n## v1.0.0\n"
+	assert.True(t, HasChangelogSection(changelog, "v1.1.0"))
+	assert.True(t, HasChangelogSection(changelog, "v1.0.0"))
+	assert.False(t, HasChangelogSection(changelog, "v1.1"))
+	assert.False(t, HasChangelogSection(changelog, "v1.0.1"))
+}
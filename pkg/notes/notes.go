@@ -0,0 +1,257 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+)
+
+var ErrInvalidTemplate = errors.New("invalid release notes template")
+
+// DefaultTemplate renders the sections as lists of pull requests, or of
+// commits without a pull request, followed by a link to the full changelog.
+const DefaultTemplate = `## What's Changed
+{{- range .Sections }}
+
+### {{ .Title }}
+{{ range .Entries }}
+* {{ .Title }}{{ if .Number }} by @{{ .Author }} in #{{ .Number }}{{ else }} ({{ .ShortSHA }}){{ end }}
+{{- end }}
+{{- end }}
+{{- if .PreviousTag }}
+
+**Full Changelog**: {{ .PreviousTag }}...{{ .Tag }}
+{{- end }}
+`
+
+// Section groups the entries of the release notes. An entry belongs to the
+// first section it matches: a breaking change for Breaking, one of the label
+// patterns of Labels, matched like semver.MatchLabel, or one of the
+// Conventional Commit types of Types. A section without any of these takes
+// all remaining entries.
+type Section struct {
+	Title    string   `yaml:"title" json:"title"`
+	Labels   []string `yaml:"labels" json:"labels,omitempty"`
+	Types    []string `yaml:"types" json:"types,omitempty"`
+	Breaking bool     `yaml:"breaking" json:"breaking,omitempty"`
+}
+
+// DefaultSections are used when no sections are configured.
+var DefaultSections = []Section{
+	{Title: "Breaking Changes", Labels: []string{"major"}, Breaking: true},
+	{Title: "Features", Labels: []string{"minor"}, Types: []string{"feat"}},
+	{Title: "Fixes", Labels: []string{"patch"}, Types: []string{"fix"}},
+	{Title: "Other Changes"},
+}
+
+// Commit is a commit of the release.
+type Commit struct {
+	SHA     string
+	Message string
+	Author  string
+	// PullRequest is the number of the pull request the commit was merged
+	// with, 0 when there is none.
+	PullRequest int
+}
+
+// PullRequest is a pull request merged into the release.
+type PullRequest struct {
+	Number int
+	Title  string
+	Author string
+	URL    string
+	Labels []string
+}
+
+// Entry is a line of the release notes: a pull request with its commits or
+// a commit merged without a pull request. Type, Scope and Breaking come from
+// the Conventional Commit title of the pull request or from its commits.
+type Entry struct {
+	Title    string
+	Author   string
+	Number   int
+	URL      string
+	SHA      string
+	Labels   []string
+	Type     string
+	Scope    string
+	Breaking bool
+	Commits  []Commit
+}
+
+// ShortSHA returns the abbreviated SHA of a commit entry.
+func (e Entry) ShortSHA() string {
+	if len(e.SHA) > 7 {
+		return e.SHA[:7]
+	}
+	return e.SHA
+}
+
+// RenderedSection is a section with its entries.
+type RenderedSection struct {
+	Title   string
+	Entries []Entry
+}
+
+// Data is what the release notes template is executed with.
+type Data struct {
+	PreviousTag string
+	Tag         string
+	Increment   string
+	// Date is the date of the release.
+	Date         time.Time
+	Commits      []Commit
+	PullRequests []PullRequest
+	// Authors are the authors of the pull requests and commits, in order of
+	// appearance.
+	Authors []string
+	// Entries are all entries, Sections only the non-empty sections.
+	Entries  []Entry
+	Sections []RenderedSection
+}
+
+// NewData groups commits and pullRequests into entries and sections.
+// DefaultSections are used when sections is empty.
+func NewData(previousTag, tag, increment string, commits []Commit, pullRequests []PullRequest, sections []Section) Data {
+	if len(sections) == 0 {
+		sections = DefaultSections
+	}
+	data := Data{
+		PreviousTag:  previousTag,
+		Tag:          tag,
+		Increment:    increment,
+		Commits:      commits,
+		PullRequests: pullRequests,
+		Entries:      newEntries(commits, pullRequests),
+	}
+	authors := map[string]bool{}
+	for _, entry := range data.Entries {
+		if entry.Author != "" && !authors[entry.Author] {
+			authors[entry.Author] = true
+			data.Authors = append(data.Authors, entry.Author)
+		}
+	}
+
+	rendered := make([]RenderedSection, len(sections))
+	for _, entry := range data.Entries {
+		for i, section := range sections {
+			if section.matches(entry) {
+				rendered[i].Entries = append(rendered[i].Entries, entry)
+				break
+			}
+		}
+	}
+	for i, section := range sections {
+		if len(rendered[i].Entries) > 0 {
+			rendered[i].Title = section.Title
+			data.Sections = append(data.Sections, rendered[i])
+		}
+	}
+	return data
+}
+
+func newEntries(commits []Commit, pullRequests []PullRequest) []Entry {
+	var entries []Entry
+	pullRequestEntries := map[int]int{}
+	for _, pullRequest := range pullRequests {
+		entry := Entry{
+			Title:  pullRequest.Title,
+			Author: pullRequest.Author,
+			Number: pullRequest.Number,
+			URL:    pullRequest.URL,
+			Labels: pullRequest.Labels,
+		}
+		if conventional, ok := semver.ParseConventionalCommit(pullRequest.Title); ok {
+			entry.setConventionalCommit(conventional)
+		}
+		pullRequestEntries[pullRequest.Number] = len(entries)
+		entries = append(entries, entry)
+	}
+
+	for _, commit := range commits {
+		conventional, ok := semver.ParseConventionalCommit(commit.Message)
+		if i, found := pullRequestEntries[commit.PullRequest]; found {
+			entry := &entries[i]
+			entry.Commits = append(entry.Commits, commit)
+			if ok && entry.Type == "" {
+				entry.setConventionalCommit(conventional)
+			}
+			entry.Breaking = entry.Breaking || conventional.Breaking
+			continue
+		}
+		entry := Entry{
+			Title:   subject(commit.Message),
+			Author:  commit.Author,
+			SHA:     commit.SHA,
+			Commits: []Commit{commit},
+		}
+		if ok {
+			entry.setConventionalCommit(conventional)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (e *Entry) setConventionalCommit(conventional semver.ConventionalCommit) {
+	e.Type = conventional.Type
+	e.Scope = conventional.Scope
+	e.Breaking = e.Breaking || conventional.Breaking
+}
+
+func (s Section) matches(entry Entry) bool {
+	if len(s.Labels) == 0 && len(s.Types) == 0 && !s.Breaking {
+		return true
+	}
+	if s.Breaking && entry.Breaking {
+		return true
+	}
+	for _, label := range entry.Labels {
+		if semver.MatchLabel(s.Labels, label) {
+			return true
+		}
+	}
+	for _, commitType := range s.Types {
+		if commitType == entry.Type {
+			return true
+		}
+	}
+	return false
+}
+
+func subject(message string) string {
+	subject, _, _ := strings.Cut(message, "\n")
+	return strings.TrimSpace(subject)
+}
+
+// Parse parses text as a release notes template, DefaultTemplate when text
+// is empty.
+func Parse(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	tmpl, err := template.New("release notes").Funcs(template.FuncMap{
+		"join": strings.Join,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+	return tmpl, nil
+}
+
+// Render executes the template text, see Parse, with data.
+func Render(text string, data Data) (string, error) {
+	tmpl, err := Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var body strings.Builder
+	if err := tmpl.Execute(&body, data); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+	return body.String(), nil
+}
@@ -0,0 +1,90 @@
+package notes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testData(sections []Section) Data {
+	commits := []Commit{
+		{SHA: "1111111aaaa", Message: "feat: add flag", Author: "Alice", PullRequest: 12},
+		{SHA: "2222222bbbb", Message: "fix: handle empty flag\n\nBREAKING CHANGE: flag is required", Author: "Alice", PullRequest: 12},
+		{SHA: "3333333cccc", Message: "fix: typo in readme", Author: "Bob"},
+		{SHA: "4444444dddd", Message: "Bump dependencies", Author: "Carol", PullRequest: 13},
+		{SHA: "5555555eeee", Message: "chore: release tooling", Author: "Bob"},
+	}
+	pullRequests := []PullRequest{
+		{Number: 12, Title: "Add flag", Author: "alice", Labels: []string{"minor"}},
+		{Number: 13, Title: "Bump dependencies", Author: "dependabot", Labels: []string{"patch", "dependencies"}},
+	}
+	return NewData("v1.0.0", "v2.0.0", "major", commits, pullRequests, sections)
+}
+
+func TestNewData(t *testing.T) {
+	data := testData(nil)
+
+	require.Len(t, data.Entries, 4)
+	assert.Equal(t, "feat", data.Entries[0].Type)
+	assert.True(t, data.Entries[0].Breaking)
+	assert.Len(t, data.Entries[0].Commits, 2)
+	assert.Equal(t, []string{"alice", "dependabot", "Bob"}, data.Authors)
+
+	var titles []string
+	for _, section := range data.Sections {
+		titles = append(titles, section.Title)
+	}
+	assert.Equal(t, []string{"Breaking Changes", "Fixes", "Other Changes"}, titles)
+	assert.Equal(t, "Add flag", data.Sections[0].Entries[0].Title)
+	assert.Equal(t, "Bump dependencies", data.Sections[1].Entries[0].Title)
+	assert.Equal(t, "fix: typo in readme", data.Sections[1].Entries[1].Title)
+	assert.Equal(t, "chore: release tooling", data.Sections[2].Entries[0].Title)
+}
+
+func TestNewDataSections(t *testing.T) {
+	data := testData([]Section{
+		{Title: "Dependencies", Labels: []string{"dep*"}},
+		{Title: "Chores", Types: []string{"chore"}},
+	})
+
+	require.Len(t, data.Sections, 2)
+	assert.Equal(t, "Dependencies", data.Sections[0].Title)
+	assert.Equal(t, 13, data.Sections[0].Entries[0].Number)
+	assert.Equal(t, "Chores", data.Sections[1].Title)
+	assert.Len(t, data.Sections[1].Entries, 1)
+}
+
+func TestRender(t *testing.T) {
+	body, err := Render("", testData(nil))
+	require.NoError(t, err)
+	assert.Equal(t, `## What's Changed
+
+### Breaking Changes
+
+* Add flag by @alice in #12
+
+### Fixes
+
+* Bump dependencies by @dependabot in #13
+* fix: typo in readme (3333333)
+
+### Other Changes
+
+* chore: release tooling (5555555)
+
+**Full Changelog**: v1.0.0...v2.0.0
+`, body)
+
+	body, err = Render(`{{ .Tag }} ({{ .Increment }}) by {{ join .Authors ", " }}`, testData(nil))
+	require.NoError(t, err)
+	assert.Equal(t, "v2.0.0 (major) by alice, dependabot, Bob", body)
+}
+
+func TestRenderInvalidTemplate(t *testing.T) {
+	_, err := Render("{{ .Tag ", Data{})
+	assert.ErrorIs(t, err, ErrInvalidTemplate)
+
+	_, err = Render("{{ .Missing }}", Data{})
+	assert.ErrorIs(t, err, ErrInvalidTemplate)
+}
@@ -2,13 +2,22 @@ package semver
 
 import (
 	"errors"
+	"regexp"
+	"strings"
 
 	"github.com/google/go-github/v65/github"
 )
 
+var ErrNoReleasableCommits = errors.New("no releasable conventional commits found")
+
+var (
+	conventionalCommitHeader = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?: (\S[^\n]*)`)
+	breakingChangeFooter     = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+)
+
 func BumpSemverVersion(version string, increment string, format string) (string, error) {
 
-	v, err := ParseVersion(version)
+	v, err := parseTagOrVersion(format, version)
 	if err != nil {
 		return "", err
 	}
@@ -19,25 +28,113 @@ func BumpSemverVersion(version string, increment string, format string) (string,
 	return v.Bump(inc).Format(format), nil
 }
 
-func ExtractSemVerIncrementFromPullRequest(pr *github.PullRequest) (Increment, error) {
-	validLabelFound := false
-	increment := IncrementPatch
+// BumpSemverPrerelease is BumpSemverVersion for pre-release series: it cuts
+// the next preid pre-release (e.g. v1.4.0-rc.1, v1.4.0-rc.2, ...) instead of
+// a final version.
+func BumpSemverPrerelease(version string, increment string, preid string, format string) (string, error) {
+	v, err := parseTagOrVersion(format, version)
+	if err != nil {
+		return "", err
+	}
+	inc, err := ParseIncrement(increment)
+	if err != nil {
+		return "", err
+	}
+	return v.BumpPrerelease(inc, preid).Format(format), nil
+}
+
+// parseTagOrVersion reads tag with format and falls back to a plain version,
+// so "1.2.3" can still be bumped into any format.
+func parseTagOrVersion(format, tag string) (Version, error) {
+	if v, err := ParseTag(format, tag); err == nil {
+		return v, nil
+	}
+	return ParseVersion(tag)
+}
+
+// ExtractSemVerIncrementFromPullRequest finds the increment from the labels
+// of pr as mapped and resolved by mapping.
+func ExtractSemVerIncrementFromPullRequest(pr *github.PullRequest, mapping LabelMapping) (Increment, error) {
+	resolution, err := ResolvePullRequestLabels(pr, mapping)
+	if err != nil {
+		return IncrementPatch, err
+	}
+	return resolution.Increment, nil
+}
+
+// ResolvePullRequestLabels is ExtractSemVerIncrementFromPullRequest
+// reporting the labels that were considered.
+func ResolvePullRequestLabels(pr *github.PullRequest, mapping LabelMapping) (LabelResolution, error) {
+	var labels []string
 	for _, label := range pr.Labels {
-		if label.Name == nil {
-			continue
+		if label.Name != nil {
+			labels = append(labels, *label.Name)
 		}
-		inc, err := ParseIncrement(*label.Name)
-		if err != nil {
+	}
+	return mapping.ResolveLabels(labels)
+}
+
+// ExtractSemVerIncrementFromCommits finds the increment from Conventional
+// Commit messages: "fix:" is a patch, "feat:" a minor and a "!" after the type
+// or a "BREAKING CHANGE:" footer a major. Other commit types do not release.
+func ExtractSemVerIncrementFromCommits(commits []*github.RepositoryCommit) (Increment, error) {
+	validCommitFound := false
+	increment := IncrementPatch
+	for _, commit := range commits {
+		inc, ok := conventionalCommitIncrement(commit.GetCommit().GetMessage())
+		if !ok {
 			continue
 		}
-		if validLabelFound {
-			return increment, errors.New("multiple valid semver labels found")
-		}
-		validLabelFound = true
-		increment = inc
+		validCommitFound = true
+		increment = MaxIncrement(increment, inc)
 	}
-	if !validLabelFound {
-		return increment, errors.New("no valid semver labels found")
+	if !validCommitFound {
+		return increment, ErrNoReleasableCommits
 	}
 	return increment, nil
 }
+
+// ConventionalCommit is a commit message following Conventional Commits,
+// e.g. "feat(api)!: drop v1 endpoints".
+type ConventionalCommit struct {
+	// Type is matched case-insensitively and given in lower case, so
+	// "Feat:" and "FEAT:" are features too.
+	Type        string
+	Scope       string
+	Description string
+	// Breaking is set by a "!" after the type or scope or by a
+	// "BREAKING CHANGE:" footer.
+	Breaking bool
+}
+
+// ParseConventionalCommit parses message, the returned bool is false when
+// message does not follow Conventional Commits.
+func ParseConventionalCommit(message string) (ConventionalCommit, bool) {
+	header := conventionalCommitHeader.FindStringSubmatch(message)
+	if header == nil {
+		return ConventionalCommit{}, false
+	}
+	return ConventionalCommit{
+		Type:        strings.ToLower(header[1]),
+		Scope:       header[2],
+		Description: strings.TrimSpace(header[4]),
+		Breaking:    header[3] == "!" || breakingChangeFooter.MatchString(message),
+	}, true
+}
+
+func conventionalCommitIncrement(message string) (Increment, bool) {
+	commit, ok := ParseConventionalCommit(message)
+	if !ok {
+		return IncrementPatch, false
+	}
+	if commit.Breaking {
+		return IncrementMajor, true
+	}
+	switch commit.Type {
+	case "feat":
+		return IncrementMinor, true
+	case "fix":
+		return IncrementPatch, true
+	}
+	return IncrementPatch, false
+}
@@ -26,6 +26,10 @@ func TestBumpSemverVersion(t *testing.T) {
 		{"1.2.3", "minor", "version-%major%.%minor%.%patch%", "version-1.3.0", nil},
 		{"1.2.3", "major", "%major%-%minor%-%patch%", "2-0-0", nil},
 
+		// Versions are read back through the format
+		{"release-1.2.3", "minor", "release-%major%.%minor%.%patch%", "release-1.3.0", nil},
+		{"api/v1.2.3", "patch", "api/v%major%.%minor%.%patch%", "api/v1.2.4", nil},
+
 		// Invalid increment
 		{"1.2.3", "invalid", "%major%.%minor%.%patch%", "", ErrInvalidIncrement},
 
@@ -62,10 +66,33 @@ func TestBumpSemverVersion(t *testing.T) {
 
 }
 
+func TestBumpSemverPrerelease(t *testing.T) {
+	tests := []struct {
+		version   string
+		increment string
+		preid     string
+		expected  string
+	}{
+		{"v1.3.2", "minor", "rc", "v1.4.0-rc.1"},
+		{"v1.4.0-rc.1", "patch", "rc", "v1.4.0-rc.2"},
+		{"v1.4.0-rc.2", "major", "rc", "v2.0.0-rc.1"},
+	}
+
+	for _, test := range tests {
+		result, err := BumpSemverPrerelease(test.version, test.increment, test.preid, "v%major%.%minor%.%patch%")
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, result)
+	}
+
+	_, err := BumpSemverPrerelease("v1.3.2", "invalid", "rc", "v%major%.%minor%.%patch%")
+	assert.Equal(t, ErrInvalidIncrement, err)
+}
+
 func TestExtractSemVerIncrementFromPullRequest(t *testing.T) {
 	tests := []struct {
 		name          string
 		labels        []*github.Label
+		mapping       LabelMapping
 		expectedInc   Increment
 		expectedError error
 	}{
@@ -118,6 +145,39 @@ func TestExtractSemVerIncrementFromPullRequest(t *testing.T) {
 			expectedInc:   IncrementPatch,
 			expectedError: errors.New("no valid semver labels found"),
 		},
+		{
+			name: "Mapped label",
+			labels: []*github.Label{
+				{
+					Name: github.String("semver:feature"),
+				},
+			},
+			mapping:       LabelMapping{Minor: []string{"semver:feature"}, Major: []string{"semver:breaking"}},
+			expectedInc:   IncrementMinor,
+			expectedError: nil,
+		},
+		{
+			name: "Mapped glob pattern",
+			labels: []*github.Label{
+				{
+					Name: github.String("Type/BugFix"),
+				},
+			},
+			mapping:       LabelMapping{Patch: []string{"type/bug*"}},
+			expectedInc:   IncrementPatch,
+			expectedError: nil,
+		},
+		{
+			name: "Default labels replaced by mapping",
+			labels: []*github.Label{
+				{
+					Name: github.String("minor"),
+				},
+			},
+			mapping:       LabelMapping{Minor: []string{"semver:feature"}},
+			expectedInc:   IncrementPatch,
+			expectedError: errors.New("no valid semver labels found"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -126,7 +186,7 @@ func TestExtractSemVerIncrementFromPullRequest(t *testing.T) {
 				Labels: tt.labels,
 			}
 
-			inc, err := ExtractSemVerIncrementFromPullRequest(pr)
+			inc, err := ExtractSemVerIncrementFromPullRequest(pr, tt.mapping)
 
 			if tt.expectedError != nil {
 				assert.EqualError(t, err, tt.expectedError.Error())
@@ -138,3 +198,107 @@ func TestExtractSemVerIncrementFromPullRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractSemVerIncrementFromCommits(t *testing.T) {
+	commits := func(messages ...string) []*github.RepositoryCommit {
+		var result []*github.RepositoryCommit
+		for _, message := range messages {
+			result = append(result, &github.RepositoryCommit{Commit: &github.Commit{Message: github.String(message)}})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name          string
+		commits       []*github.RepositoryCommit
+		expectedInc   Increment
+		expectedError error
+	}{
+		{
+			name:        "Fix",
+			commits:     commits("fix: handle nil labels"),
+			expectedInc: IncrementPatch,
+		},
+		{
+			name:        "Feature with scope",
+			commits:     commits("fix: handle nil labels", "feat(cli): add validate command"),
+			expectedInc: IncrementMinor,
+		},
+		{
+			name:        "Types in other case",
+			commits:     commits("FIX: handle nil labels", "Feat: add validate command"),
+			expectedInc: IncrementMinor,
+		},
+		{
+			name:        "Breaking change marker",
+			commits:     commits("feat!: drop node16 support", "fix: typo"),
+			expectedInc: IncrementMajor,
+		},
+		{
+			name:        "Breaking change footer",
+			commits:     commits("fix: rename input\n\nBREAKING CHANGE: tag input is now next_tag"),
+			expectedInc: IncrementMajor,
+		},
+		{
+			name:          "No releasable commits",
+			commits:       commits("chore: bump deps", "docs: fix readme", "Merge branch 'main'"),
+			expectedInc:   IncrementPatch,
+			expectedError: ErrNoReleasableCommits,
+		},
+		{
+			name:          "No commits",
+			commits:       nil,
+			expectedInc:   IncrementPatch,
+			expectedError: ErrNoReleasableCommits,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inc, err := ExtractSemVerIncrementFromCommits(tt.commits)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedInc, inc)
+		})
+	}
+}
+
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected ConventionalCommit
+		ok       bool
+	}{
+		{
+			message:  "fix: handle nil labels",
+			expected: ConventionalCommit{Type: "fix", Description: "handle nil labels"},
+			ok:       true,
+		},
+		{
+			message:  "feat(api)!: drop v1 endpoints\n\nThey were deprecated.",
+			expected: ConventionalCommit{Type: "feat", Scope: "api", Description: "drop v1 endpoints", Breaking: true},
+			ok:       true,
+		},
+		{
+			message:  "fix: rename input\n\nBREAKING CHANGE: tag input is now next_tag",
+			expected: ConventionalCommit{Type: "fix", Description: "rename input", Breaking: true},
+			ok:       true,
+		},
+		{
+			message:  "Feat(API): add v2 endpoints",
+			expected: ConventionalCommit{Type: "feat", Scope: "API", Description: "add v2 endpoints"},
+			ok:       true,
+		},
+		{
+			message: "Merge branch 'main'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			commit, ok := ParseConventionalCommit(tt.message)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, commit)
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	version "github.com/blang/semver/v4"
+)
+
+const shortSHALength = 7
+
+// FormatContext holds the values of the placeholders that do not come from
+// the version itself but from the environment the tag is created in.
+type FormatContext struct {
+	SHA       string
+	RunNumber string
+	Date      time.Time
+}
+
+// Expand replaces the %sha%, %short_sha%, %date% and %run_number%
+// placeholders in format. Version placeholders are left for Version.Format.
+func (c FormatContext) Expand(format string) string {
+	shortSHA := c.SHA
+	if len(shortSHA) > shortSHALength {
+		shortSHA = shortSHA[:shortSHALength]
+	}
+	date := ""
+	if !c.Date.IsZero() {
+		date = c.Date.Format("20060102")
+	}
+
+	return strings.NewReplacer(
+		"%sha%", c.SHA,
+		"%short_sha%", shortSHA,
+		"%date%", date,
+		"%run_number%", c.RunNumber,
+	).Replace(format)
+}
+
+// DefaultTagFormat is the tag format used when none is configured.
+const DefaultTagFormat = "v%major%.%minor%.%patch%"
+
+var (
+	ErrInvalidTagFormat  = errors.New("invalid tag format")
+	ErrTagFormatMismatch = errors.New("tag does not match tag format")
+)
+
+const identifiersPattern = `[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*`
+
+// PlaceholderPattern matches the placeholders of a tag format, e.g. %major%.
+var PlaceholderPattern = regexp.MustCompile(`%[a-z_]+%`)
+
+var (
+	placeholderPatterns = map[string]string{
+		"%major%":      `\d+`,
+		"%minor%":      `\d+`,
+		"%patch%":      `\d+`,
+		"%prerelease%": identifiersPattern,
+		"%sha%":        `[0-9a-f]+`,
+		"%short_sha%":  `[0-9a-f]+`,
+		"%date%":       `\d{8}`,
+		"%run_number%": `\d+`,
+	}
+
+	// partIntros match the text at the end of a literal that introduces a
+	// %prerelease% placeholder, e.g. "-" or "-rc.". It is left out together
+	// with an empty placeholder.
+	partIntros = map[string]*regexp.Regexp{
+		"%prerelease%": regexp.MustCompile(`(?:-[0-9A-Za-z]*\.?|[._+-])$`),
+	}
+
+	// capturedPlaceholders are the placeholders the version is read from
+	capturedPlaceholders = map[string]string{
+		"%major%":      "major",
+		"%minor%":      "minor",
+		"%patch%":      "patch",
+		"%prerelease%": "prerelease",
+	}
+)
+
+// TagParser reads versions back from tags written with a tag format, so
+// "release-%major%.%minor%.%patch%" parses "release-1.2.3" as v1.2.3.
+type TagParser struct {
+	format string
+	re     *regexp.Regexp
+}
+
+// NewTagParser compiles format into a TagParser. The format has to contain
+// the %major%, %minor% and %patch% placeholders. Pre-release and build
+// metadata parts are accepted the same way Version.Format adds them when the
+// format has no %prerelease% placeholder or "+", and the pre-release is
+// optional together with the text introducing it when it has.
+func NewTagParser(format string) (*TagParser, error) {
+	for _, required := range []string{"%major%", "%minor%", "%patch%"} {
+		if !strings.Contains(format, required) {
+			return nil, fmt.Errorf("%w: %q is missing %s", ErrInvalidTagFormat, format, required)
+		}
+	}
+
+	b := tagPatternBuilder{
+		prereleaseAdded: strings.Contains(format, "%prerelease%"),
+		captured:        map[string]bool{},
+	}
+	b.pattern.WriteString("^")
+	last := 0
+	for _, loc := range PlaceholderPattern.FindAllStringIndex(format, -1) {
+		literal, placeholder := format[last:loc[0]], format[loc[0]:loc[1]]
+		if intro, ok := partIntros[placeholder]; ok {
+			b.writeOptionalPart(literal, placeholder, intro)
+		} else {
+			b.writeLiteral(literal)
+			b.writePlaceholder(placeholder)
+		}
+		last = loc[1]
+	}
+	b.writeLiteral(format[last:])
+	b.writePrerelease()
+	if !strings.Contains(format, "+") {
+		b.pattern.WriteString(`(?:\+(?P<build>` + identifiersPattern + `))?`)
+	}
+	b.pattern.WriteString("$")
+
+	re, err := regexp.Compile(b.pattern.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidTagFormat, format, err)
+	}
+	return &TagParser{format: format, re: re}, nil
+}
+
+type tagPatternBuilder struct {
+	pattern         strings.Builder
+	prereleaseAdded bool
+	captured        map[string]bool
+}
+
+// writeLiteral quotes literal format text. Like Version.Format, an implicit
+// pre-release goes in front of build metadata given in the format.
+func (b *tagPatternBuilder) writeLiteral(literal string) {
+	if i := strings.Index(literal, "+"); i >= 0 {
+		b.pattern.WriteString(regexp.QuoteMeta(literal[:i]))
+		b.writePrerelease()
+		literal = literal[i:]
+	}
+	b.pattern.WriteString(regexp.QuoteMeta(literal))
+}
+
+func (b *tagPatternBuilder) writePlaceholder(placeholder string) {
+	sub, ok := placeholderPatterns[placeholder]
+	if !ok {
+		b.pattern.WriteString(regexp.QuoteMeta(placeholder))
+		return
+	}
+	name, capture := capturedPlaceholders[placeholder]
+	if !capture || b.captured[name] {
+		b.pattern.WriteString(`(?:` + sub + `)`)
+		return
+	}
+	b.captured[name] = true
+	b.pattern.WriteString(`(?P<` + name + `>` + sub + `)`)
+}
+
+// writeOptionalPart writes literal and the %prerelease% placeholder, which
+// is optional together with the end of literal that intro matches.
+func (b *tagPatternBuilder) writeOptionalPart(literal, placeholder string, intro *regexp.Regexp) {
+	start := len(literal)
+	if loc := intro.FindStringIndex(literal); loc != nil {
+		start = loc[0]
+	}
+	b.writeLiteral(literal[:start])
+	b.pattern.WriteString(`(?:` + regexp.QuoteMeta(literal[start:]))
+	b.writePlaceholder(placeholder)
+	b.pattern.WriteString(`)?`)
+}
+
+// dropPart removes placeholder from format together with the text
+// introducing it, e.g. "-%prerelease%" for versions without a pre-release.
+func dropPart(format, placeholder string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(format, placeholder)
+		if i < 0 {
+			b.WriteString(format)
+			return b.String()
+		}
+		literal := format[:i]
+		if loc := partIntros[placeholder].FindStringIndex(literal); loc != nil {
+			literal = literal[:loc[0]]
+		}
+		b.WriteString(literal)
+		format = format[i+len(placeholder):]
+	}
+}
+
+func (b *tagPatternBuilder) writePrerelease() {
+	if b.prereleaseAdded {
+		return
+	}
+	b.prereleaseAdded = true
+	b.pattern.WriteString(`(?:-(?P<prerelease>` + identifiersPattern + `))?`)
+}
+
+// Parse extracts the version from tag.
+func (p *TagParser) Parse(tag string) (Version, error) {
+	match := p.re.FindStringSubmatch(tag)
+	if match == nil {
+		return Version{}, fmt.Errorf("%w: %q does not match %q", ErrTagFormatMismatch, tag, p.format)
+	}
+	group := func(name string) string {
+		if i := p.re.SubexpIndex(name); i >= 0 {
+			return match[i]
+		}
+		return ""
+	}
+
+	s := group("major") + "." + group("minor") + "." + group("patch")
+	if pre := group("prerelease"); pre != "" {
+		s += "-" + pre
+	}
+	if build := group("build"); build != "" {
+		s += "+" + build
+	}
+	v, err := version.Parse(s)
+	if err != nil {
+		return Version{}, fmt.Errorf("%w: %q: %v", ErrTagFormatMismatch, tag, err)
+	}
+	return fromSemver(v), nil
+}
+
+// ParseTag extracts the version from a tag written with format.
+func ParseTag(format, tag string) (Version, error) {
+	parser, err := NewTagParser(format)
+	if err != nil {
+		return Version{}, err
+	}
+	return parser.Parse(tag)
+}
+
+// FilterTags returns the tags matching format whose version satisfies
+// versionRange, highest version first. Tags that do not match the format are
+// ignored.
+func FilterTags(tags []string, format, versionRange string) ([]string, error) {
+	parser, err := NewTagParser(format)
+	if err != nil {
+		return nil, err
+	}
+	expectedRange, err := version.ParseRange(versionRange)
+	if err != nil {
+		return nil, err
+	}
+
+	type parsedTag struct {
+		tag     string
+		version version.Version
+	}
+	var matching []parsedTag
+	for _, tag := range tags {
+		v, err := parser.Parse(tag)
+		if err != nil {
+			continue
+		}
+		if expectedRange(v.semver()) {
+			matching = append(matching, parsedTag{tag: tag, version: v.semver()})
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].version.GT(matching[j].version)
+	})
+
+	filtered := make([]string, 0, len(matching))
+	for _, m := range matching {
+		filtered = append(filtered, m.tag)
+	}
+	return filtered, nil
+}
+
+// RangeBelow returns the range of the versions satisfying versionRange that
+// are lower than v. The bound is added to every alternative of an "||"
+// range, appended to the whole range it would only restrict the last one.
+func RangeBelow(versionRange string, v Version) (string, error) {
+	bound := "<" + v.Format("%major%.%minor%.%patch%")
+	if strings.TrimSpace(versionRange) == "" {
+		return bound, nil
+	}
+	if _, err := version.ParseRange(versionRange); err != nil {
+		return "", err
+	}
+	alternatives := strings.Split(versionRange, "||")
+	for i, alternative := range alternatives {
+		alternatives[i] = strings.TrimSpace(alternative) + " " + bound
+	}
+	return strings.Join(alternatives, " || "), nil
+}
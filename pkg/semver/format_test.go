@@ -0,0 +1,173 @@
+package semver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatContextExpand(t *testing.T) {
+	ctx := FormatContext{
+		SHA:       "abc1234def5678",
+		RunNumber: "456",
+		Date:      time.Date(2024, time.March, 7, 12, 0, 0, 0, time.UTC),
+	}
+
+	cases := []struct {
+		format   string
+		expected string
+	}{
+		{"v%major%.%minor%.%patch%+sha.%short_sha%", "v%major%.%minor%.%patch%+sha.abc1234"},
+		{"v%major%.%minor%.%patch%+build.%run_number%", "v%major%.%minor%.%patch%+build.456"},
+		{"%sha%", "abc1234def5678"},
+		{"%date%", "20240307"},
+	}
+
+	for _, testCase := range cases {
+		require.Equal(t, testCase.expected, ctx.Expand(testCase.format))
+	}
+
+	require.Equal(t, "-", FormatContext{}.Expand("%date%-%short_sha%"))
+}
+
+func TestParseTag(t *testing.T) {
+	cases := []struct {
+		format string
+		tag    string
+
+		expectedVersion Version
+		expectedError   error
+	}{
+		{
+			format:          "v%major%.%minor%.%patch%",
+			tag:             "v1.2.3",
+			expectedVersion: Version{major: 1, minor: 2, patch: 3},
+		},
+		{
+			format:          "release-%major%.%minor%.%patch%",
+			tag:             "release-10.0.7",
+			expectedVersion: Version{major: 10, minor: 0, patch: 7},
+		},
+		{
+			format:          "api/v%major%.%minor%.%patch%",
+			tag:             "api/v1.4.0-rc.2",
+			expectedVersion: Version{major: 1, minor: 4, patch: 0, prerelease: []string{"rc", "2"}},
+		},
+		{
+			format:          "v%major%.%minor%.%patch%+sha.%short_sha%",
+			tag:             "v1.4.0-rc.2+sha.abc1234",
+			expectedVersion: Version{major: 1, minor: 4, patch: 0, prerelease: []string{"rc", "2"}},
+		},
+		{
+			format:          "%major%_%minor%_%patch%_%prerelease%",
+			tag:             "1_2_3_beta.1",
+			expectedVersion: Version{major: 1, minor: 2, patch: 3, prerelease: []string{"beta", "1"}},
+		},
+		{
+			format:          "v%major%.%minor%.%patch%",
+			tag:             "v1.2.3+build.456",
+			expectedVersion: Version{major: 1, minor: 2, patch: 3, build: []string{"build", "456"}},
+		},
+		{
+			format:        "v%major%.%minor%.%patch%",
+			tag:           "1.2.3",
+			expectedError: ErrTagFormatMismatch,
+		},
+		{
+			format:        "release-%major%.%minor%.%patch%",
+			tag:           "v1.2.3",
+			expectedError: ErrTagFormatMismatch,
+		},
+		{
+			format:        "v%major%.%minor%.%patch%",
+			tag:           "v1",
+			expectedError: ErrTagFormatMismatch,
+		},
+		{
+			format:        "v%major%.%minor%",
+			tag:           "v1.2",
+			expectedError: ErrInvalidTagFormat,
+		},
+	}
+
+	for _, testCase := range cases {
+		v, err := ParseTag(testCase.format, testCase.tag)
+
+		require.ErrorIs(t, err, testCase.expectedError, testCase.tag)
+		require.Equal(t, testCase.expectedVersion, v, testCase.tag)
+	}
+}
+
+func TestParseTagRoundTrip(t *testing.T) {
+	versions := []Version{
+		{major: 1, minor: 2, patch: 3},
+		{major: 1, minor: 4, patch: 0, prerelease: []string{"rc", "1"}},
+	}
+	formats := []string{
+		"v%major%.%minor%.%patch%",
+		"release-%major%.%minor%.%patch%",
+		"libs/auth/v%major%.%minor%.%patch%",
+		"%major%.%minor%.%patch%-RC",
+		"v%major%.%minor%.%patch%-%prerelease%",
+		"v%major%.%minor%.%patch%+build.456",
+		"%major%_%minor%_%patch%_%prerelease%",
+	}
+
+	for _, format := range formats {
+		for _, v := range versions {
+			parsed, err := ParseTag(format, v.Format(format))
+
+			require.NoError(t, err)
+			require.Equal(t, v, parsed)
+		}
+	}
+}
+
+func TestFilterTags(t *testing.T) {
+	tags := []string{"v1.0.0", "v1.10.0", "v1.9.0", "release-3.0.0", "v2.0.0-rc.1", "v1", "v1.4", "latest"}
+
+	filtered, err := FilterTags(tags, "v%major%.%minor%.%patch%", ">0.0.0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"v2.0.0-rc.1", "v1.10.0", "v1.9.0", "v1.0.0"}, filtered)
+
+	filtered, err = FilterTags(tags, "v%major%.%minor%.%patch%", ">=1.0.0 <1.10.0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"v1.9.0", "v1.0.0"}, filtered)
+
+	filtered, err = FilterTags(tags, "release-%major%.%minor%.%patch%", ">0.0.0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"release-3.0.0"}, filtered)
+
+	_, err = FilterTags(tags, "v%major%.%minor%.%patch%", "not a range")
+	require.Error(t, err)
+}
+
+func TestRangeBelow(t *testing.T) {
+	tags := []string{"v1.0.0", "v1.9.0", "v2.0.0", "v3.0.0-rc.1", "v3.0.0-rc.2", "v3.1.0"}
+	latest, err := ParseVersion("3.0.0-rc.2")
+	require.NoError(t, err)
+
+	tests := []struct {
+		versionRange string
+		expected     string
+		filtered     []string
+	}{
+		{">0.0.0", ">0.0.0 <3.0.0-rc.2", []string{"v3.0.0-rc.1", "v2.0.0", "v1.9.0", "v1.0.0"}},
+		{"<2.0.0 || >=3.0.0-0", "<2.0.0 <3.0.0-rc.2 || >=3.0.0-0 <3.0.0-rc.2", []string{"v3.0.0-rc.1", "v1.9.0", "v1.0.0"}},
+		{"", "<3.0.0-rc.2", []string{"v3.0.0-rc.1", "v2.0.0", "v1.9.0", "v1.0.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.versionRange, func(t *testing.T) {
+			below, err := RangeBelow(tt.versionRange, latest)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, below)
+			filtered, err := FilterTags(tags, "v%major%.%minor%.%patch%", below)
+			require.NoError(t, err)
+			require.Equal(t, tt.filtered, filtered)
+		})
+	}
+
+	_, err = RangeBelow("not a range", latest)
+	require.Error(t, err)
+}
@@ -0,0 +1,158 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+var (
+	ErrNoValidLabels       = errors.New("no valid semver labels found")
+	ErrMultipleValidLabels = errors.New("multiple valid semver labels found")
+)
+
+// LabelMapping maps pull request labels to increments or to skipping the
+// release. Each list holds label names or path.Match glob patterns such as
+// "semver:*" or "type/bug*", matched case-insensitively. An empty list keeps
+// the default labels of DefaultLabelMapping.
+type LabelMapping struct {
+	Major []string `yaml:"major" json:"major,omitempty"`
+	Minor []string `yaml:"minor" json:"minor,omitempty"`
+	Patch []string `yaml:"patch" json:"patch,omitempty"`
+	Skip  []string `yaml:"skip" json:"skip,omitempty"`
+	// Policy resolves pull requests with several labels mapping to an
+	// increment, LabelPolicyError when empty.
+	Policy LabelPolicy `yaml:"policy" json:"policy,omitempty"`
+}
+
+// LabelPolicy decides which increment wins when several labels of a pull
+// request map to an increment.
+type LabelPolicy string
+
+const (
+	// LabelPolicyError fails the release.
+	LabelPolicyError LabelPolicy = "error"
+	// LabelPolicyHighest takes the largest increment, e.g. minor for minor
+	// and patch.
+	LabelPolicyHighest LabelPolicy = "highest"
+	// LabelPolicyLowest takes the smallest increment.
+	LabelPolicyLowest LabelPolicy = "lowest"
+	// LabelPolicyFirst takes the increment of the first label.
+	LabelPolicyFirst LabelPolicy = "first"
+)
+
+// DefaultLabelMapping is the mapping used when none is configured.
+var DefaultLabelMapping = LabelMapping{
+	Major: []string{"major"},
+	Minor: []string{"minor"},
+	Patch: []string{"patch"},
+	Skip:  []string{"skip-release", "skipRelease"},
+}
+
+// Validate checks the policy and that all patterns are valid glob patterns.
+func (m LabelMapping) Validate() error {
+	switch m.Policy {
+	case "", LabelPolicyError, LabelPolicyHighest, LabelPolicyLowest, LabelPolicyFirst:
+	default:
+		return fmt.Errorf("invalid label policy %q, expected error, highest, lowest or first", m.Policy)
+	}
+	for _, patterns := range [][]string{m.Major, m.Minor, m.Patch, m.Skip} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid label pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// SkipPatterns returns the patterns of labels skipping the release.
+func (m LabelMapping) SkipPatterns() []string {
+	return orDefault(m.Skip, DefaultLabelMapping.Skip)
+}
+
+// Increment returns the increment label maps to. A label matching several
+// increments maps to the largest one.
+func (m LabelMapping) Increment(label string) (Increment, bool) {
+	for _, rule := range []struct {
+		increment Increment
+		patterns  []string
+	}{
+		{IncrementMajor, orDefault(m.Major, DefaultLabelMapping.Major)},
+		{IncrementMinor, orDefault(m.Minor, DefaultLabelMapping.Minor)},
+		{IncrementPatch, orDefault(m.Patch, DefaultLabelMapping.Patch)},
+	} {
+		if MatchLabel(rule.patterns, label) {
+			return rule.increment, true
+		}
+	}
+	return "", false
+}
+
+// LabelResolution describes how the increment of a pull request was found
+// from its labels.
+type LabelResolution struct {
+	Policy LabelPolicy
+	// Considered are the labels mapping to an increment, in the order of the
+	// pull request.
+	Considered []string
+	Increment  Increment
+}
+
+func (r LabelResolution) String() string {
+	return fmt.Sprintf("increment %s from labels [%s] with policy %s", r.Increment, strings.Join(r.Considered, ", "), r.Policy)
+}
+
+// ResolveLabels finds the increment labels map to. Labels mapping to no
+// increment are ignored, several increments are resolved by the policy of
+// the mapping.
+func (m LabelMapping) ResolveLabels(labels []string) (LabelResolution, error) {
+	resolution := LabelResolution{Policy: m.Policy}
+	if resolution.Policy == "" {
+		resolution.Policy = LabelPolicyError
+	}
+	var increments []Increment
+	for _, label := range labels {
+		if inc, ok := m.Increment(label); ok {
+			resolution.Considered = append(resolution.Considered, label)
+			increments = append(increments, inc)
+		}
+	}
+
+	switch {
+	case len(increments) == 0:
+		return resolution, ErrNoValidLabels
+	case len(increments) > 1 && resolution.Policy == LabelPolicyError:
+		return resolution, ErrMultipleValidLabels
+	}
+	resolution.Increment = increments[0]
+	for _, inc := range increments[1:] {
+		switch resolution.Policy {
+		case LabelPolicyHighest:
+			resolution.Increment = MaxIncrement(resolution.Increment, inc)
+		case LabelPolicyLowest:
+			if incrementRanks[inc] < incrementRanks[resolution.Increment] {
+				resolution.Increment = inc
+			}
+		}
+	}
+	return resolution, nil
+}
+
+// MatchLabel reports whether label matches any of patterns.
+func MatchLabel(patterns []string, label string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(label)); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func orDefault(patterns, defaults []string) []string {
+	if len(patterns) == 0 {
+		return defaults
+	}
+	return patterns
+}
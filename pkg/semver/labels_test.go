@@ -0,0 +1,89 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelMappingIncrement(t *testing.T) {
+	mapping := LabelMapping{
+		Major: []string{"semver:breaking", "breaking*"},
+		Minor: []string{"semver:feature", "type/feat*"},
+	}
+
+	tests := []struct {
+		label       string
+		expectedInc Increment
+		expectedOk  bool
+	}{
+		{label: "semver:breaking", expectedInc: IncrementMajor, expectedOk: true},
+		{label: "SEMVER:Feature", expectedInc: IncrementMinor, expectedOk: true},
+		{label: "type/feature", expectedInc: IncrementMinor, expectedOk: true},
+		{label: "breaking-change", expectedInc: IncrementMajor, expectedOk: true},
+		{label: "patch", expectedInc: IncrementPatch, expectedOk: true},
+		{label: "minor", expectedOk: false},
+		{label: "documentation", expectedOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			inc, ok := mapping.Increment(tt.label)
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, tt.expectedInc, inc)
+		})
+	}
+}
+
+func TestLabelMappingSkipPatterns(t *testing.T) {
+	assert.Equal(t, []string{"skip-release", "skipRelease"}, LabelMapping{}.SkipPatterns())
+	assert.Equal(t, []string{"no-release"}, LabelMapping{Skip: []string{"no-release"}}.SkipPatterns())
+}
+
+func TestLabelMappingValidate(t *testing.T) {
+	assert.NoError(t, LabelMapping{Minor: []string{"semver:*", "type/[fF]eature"}}.Validate())
+	assert.Error(t, LabelMapping{Skip: []string{"skip-[release"}}.Validate())
+}
+
+func TestMatchLabel(t *testing.T) {
+	assert.True(t, MatchLabel([]string{"skip-release"}, "Skip-Release"))
+	assert.True(t, MatchLabel([]string{"no-*"}, "no-release"))
+	assert.False(t, MatchLabel([]string{"no-*"}, "release"))
+}
+
+func TestLabelMappingResolveLabels(t *testing.T) {
+	labels := []string{"bug", "patch", "minor", "documentation"}
+
+	tests := []struct {
+		policy        LabelPolicy
+		expectedInc   Increment
+		expectedError string
+	}{
+		{policy: "", expectedError: "multiple valid semver labels found"},
+		{policy: LabelPolicyError, expectedError: "multiple valid semver labels found"},
+		{policy: LabelPolicyHighest, expectedInc: IncrementMinor},
+		{policy: LabelPolicyLowest, expectedInc: IncrementPatch},
+		{policy: LabelPolicyFirst, expectedInc: IncrementPatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			resolution, err := LabelMapping{Policy: tt.policy}.ResolveLabels(labels)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedInc, resolution.Increment)
+			assert.Equal(t, []string{"patch", "minor"}, resolution.Considered)
+		})
+	}
+
+	resolution, err := LabelMapping{Policy: LabelPolicyHighest}.ResolveLabels([]string{"minor", "major", "patch"})
+	assert.NoError(t, err)
+	assert.Equal(t, IncrementMajor, resolution.Increment)
+	assert.Equal(t, "increment major from labels [minor, major, patch] with policy highest", resolution.String())
+
+	_, err = LabelMapping{Policy: LabelPolicyHighest}.ResolveLabels([]string{"bug"})
+	assert.EqualError(t, err, "no valid semver labels found")
+}
@@ -19,27 +19,56 @@ const (
 	IncrementMajor Increment = "major"
 )
 
+var incrementRanks = map[Increment]int{
+	IncrementPatch: 1,
+	IncrementMinor: 2,
+	IncrementMajor: 3,
+}
+
+// MaxIncrement returns the larger of two increments.
+func MaxIncrement(a, b Increment) Increment {
+	if incrementRanks[b] > incrementRanks[a] {
+		return b
+	}
+	return a
+}
+
 type Version struct {
-	major uint64
-	minor uint64
-	patch uint64
+	major      uint64
+	minor      uint64
+	patch      uint64
+	prerelease []string
+	build      []string
 }
 
+// Bump returns the next version for the given increment. Bumping a
+// pre-release promotes it to its final version when the increment is already
+// covered by the pre-release (e.g. patch on v1.4.0-rc.2 gives v1.4.0, major
+// on v2.0.0-rc.1 gives v2.0.0).
 func (v Version) Bump(inc Increment) Version {
 	switch inc {
 	case IncrementPatch:
+		if v.IsPrerelease() {
+			return v.release()
+		}
 		return Version{
 			patch: v.patch + 1,
 			minor: v.minor,
 			major: v.major,
 		}
 	case IncrementMinor:
+		if v.IsPrerelease() && v.patch == 0 {
+			return v.release()
+		}
 		return Version{
 			patch: 0,
 			minor: v.minor + 1,
 			major: v.major,
 		}
 	case IncrementMajor:
+		if v.IsPrerelease() && v.patch == 0 && v.minor == 0 {
+			return v.release()
+		}
 		return Version{
 			patch: 0,
 			minor: 0,
@@ -50,17 +79,129 @@ func (v Version) Bump(inc Increment) Version {
 	return v
 }
 
+// BumpPrerelease returns the next version of the pre-release series named
+// preid (e.g. "rc"). A version already in that series for the same release
+// gets its counter increased (v1.4.0-rc.1 -> v1.4.0-rc.2), otherwise the
+// version is bumped by inc and a new series is started (v1.3.2 -> v1.4.0-rc.1).
+// A new series that would sort below v, like beta after v1.4.0-rc.3, starts
+// at the release after it instead (v1.5.0-beta.1).
+func (v Version) BumpPrerelease(inc Increment, preid string) Version {
+	ids := strings.Split(preid, ".")
+	next := v.Bump(inc)
+	if counter, ok := v.seriesCounter(ids); ok && next.sameRelease(v) {
+		next.prerelease = append(ids, strconv.FormatUint(counter+1, 10))
+		return next
+	}
+	next.prerelease = append(ids, "1")
+	if next.semver().LTE(v.semver()) {
+		next = next.release().Bump(inc)
+		next.prerelease = append(ids, "1")
+	}
+	return next
+}
+
+// Major returns the major version of v.
+func (v Version) Major() uint64 {
+	return v.major
+}
+
+// Minor returns the minor version of v.
+func (v Version) Minor() uint64 {
+	return v.minor
+}
+
+// Patch returns the patch version of v.
+func (v Version) Patch() uint64 {
+	return v.patch
+}
+
+// IsPrerelease reports whether v carries pre-release identifiers.
+func (v Version) IsPrerelease() bool {
+	return len(v.prerelease) > 0
+}
+
+// Prerelease returns the dot separated pre-release identifiers of v.
+func (v Version) Prerelease() string {
+	return strings.Join(v.prerelease, ".")
+}
+
+// Build returns the dot separated build metadata identifiers of v.
+func (v Version) Build() string {
+	return strings.Join(v.build, ".")
+}
+
+func (v Version) release() Version {
+	return Version{
+		major: v.major,
+		minor: v.minor,
+		patch: v.patch,
+	}
+}
+
+func (v Version) sameRelease(other Version) bool {
+	return v.major == other.major && v.minor == other.minor && v.patch == other.patch
+}
+
+// seriesCounter returns the numeric counter following ids in the pre-release
+// of v, e.g. 2 for rc.2 when ids is [rc].
+func (v Version) seriesCounter(ids []string) (uint64, bool) {
+	if len(v.prerelease) != len(ids)+1 {
+		return 0, false
+	}
+	for i, id := range ids {
+		if v.prerelease[i] != id {
+			return 0, false
+		}
+	}
+	counter, err := strconv.ParseUint(v.prerelease[len(ids)], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return counter, true
+}
+
 func (v Version) String() string {
-	return fmt.Sprintf("v%d.%d.%d", v.major, v.minor, v.patch)
+	s := fmt.Sprintf("v%d.%d.%d", v.major, v.minor, v.patch)
+	if v.IsPrerelease() {
+		s += "-" + v.Prerelease()
+	}
+	if len(v.build) > 0 {
+		s += "+" + v.Build()
+	}
+	return s
 }
 
+// Format renders v using the %major%, %minor%, %patch% and %prerelease%
+// placeholders. When the format has no %prerelease% placeholder, the
+// pre-release is added as "-<identifiers>", and build metadata of v is added
+// as "+<identifiers>" unless the format gives its own, e.g. "+sha.%short_sha%"
+// expanded by FormatContext. An empty %prerelease% is left out together with
+// the text introducing it, e.g. "v%major%.%minor%.%patch%-%prerelease%"
+// renders v1.2.3 without a trailing "-".
 func (v Version) Format(format string) string {
 	formatted := format
+	if !v.IsPrerelease() {
+		formatted = dropPart(formatted, "%prerelease%")
+	}
 
 	formatted = strings.ReplaceAll(formatted, "%major%", strconv.FormatUint(v.major, 10))
 	formatted = strings.ReplaceAll(formatted, "%minor%", strconv.FormatUint(v.minor, 10))
 	formatted = strings.ReplaceAll(formatted, "%patch%", strconv.FormatUint(v.patch, 10))
 
+	if strings.Contains(formatted, "%prerelease%") {
+		formatted = strings.ReplaceAll(formatted, "%prerelease%", v.Prerelease())
+	} else if v.IsPrerelease() {
+		// the pre-release has to precede build metadata given in the format
+		if i := strings.Index(formatted, "+"); i >= 0 {
+			formatted = formatted[:i] + "-" + v.Prerelease() + formatted[i:]
+		} else {
+			formatted += "-" + v.Prerelease()
+		}
+	}
+	if len(v.build) > 0 && !strings.Contains(formatted, "+") {
+		formatted += "+" + v.Build()
+	}
+
 	return formatted
 }
 
@@ -70,11 +211,39 @@ func ParseVersion(input string) (Version, error) {
 		return Version{}, err
 	}
 
+	return fromSemver(v), nil
+}
+
+func fromSemver(v version.Version) Version {
+	var prerelease []string
+	for _, pre := range v.Pre {
+		prerelease = append(prerelease, pre.String())
+	}
+
 	return Version{
-		major: v.Major,
-		minor: v.Minor,
-		patch: v.Patch,
-	}, nil
+		major:      v.Major,
+		minor:      v.Minor,
+		patch:      v.Patch,
+		prerelease: prerelease,
+		build:      v.Build,
+	}
+}
+
+func (v Version) semver() version.Version {
+	var pre []version.PRVersion
+	for _, id := range v.prerelease {
+		// identifiers come from a parsed version, so they are valid
+		prVersion, _ := version.NewPRVersion(id)
+		pre = append(pre, prVersion)
+	}
+
+	return version.Version{
+		Major: v.major,
+		Minor: v.minor,
+		Patch: v.patch,
+		Pre:   pre,
+		Build: v.build,
+	}
 }
 
 func ParseIncrement(inc string) (Increment, error) {
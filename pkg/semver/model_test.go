@@ -33,6 +33,24 @@ func TestVersionBump(t *testing.T) {
 			increment:       IncrementMajor,
 			expectedVersion: "v2.0.0",
 		},
+		{
+			name:            "patch bump promotes pre-release",
+			version:         Version{major: 1, minor: 4, patch: 0, prerelease: []string{"rc", "2"}},
+			increment:       IncrementPatch,
+			expectedVersion: "v1.4.0",
+		},
+		{
+			name:            "minor bump promotes minor pre-release",
+			version:         Version{major: 1, minor: 4, patch: 0, prerelease: []string{"rc", "2"}},
+			increment:       IncrementMinor,
+			expectedVersion: "v1.4.0",
+		},
+		{
+			name:            "major bump of minor pre-release",
+			version:         Version{major: 1, minor: 4, patch: 0, prerelease: []string{"rc", "2"}},
+			increment:       IncrementMajor,
+			expectedVersion: "v2.0.0",
+		},
 	}
 
 	for _, testCase := range cases {
@@ -42,6 +60,74 @@ func TestVersionBump(t *testing.T) {
 	}
 }
 
+func TestVersionBumpPrerelease(t *testing.T) {
+	cases := []struct {
+		name string
+
+		version   Version
+		increment Increment
+		preid     string
+
+		expectedVersion string
+	}{
+		{
+			name:            "start series from release",
+			version:         Version{major: 1, minor: 3, patch: 2},
+			increment:       IncrementMinor,
+			preid:           "rc",
+			expectedVersion: "v1.4.0-rc.1",
+		},
+		{
+			name:            "continue series",
+			version:         Version{major: 1, minor: 4, patch: 0, prerelease: []string{"rc", "1"}},
+			increment:       IncrementPatch,
+			preid:           "rc",
+			expectedVersion: "v1.4.0-rc.2",
+		},
+		{
+			name:            "continue series with covered increment",
+			version:         Version{major: 1, minor: 4, patch: 0, prerelease: []string{"rc", "9"}},
+			increment:       IncrementMinor,
+			preid:           "rc",
+			expectedVersion: "v1.4.0-rc.10",
+		},
+		{
+			name:            "larger increment starts new series",
+			version:         Version{major: 1, minor: 4, patch: 0, prerelease: []string{"rc", "2"}},
+			increment:       IncrementMajor,
+			preid:           "rc",
+			expectedVersion: "v2.0.0-rc.1",
+		},
+		{
+			name:            "other identifier starts new series",
+			version:         Version{major: 1, minor: 4, patch: 0, prerelease: []string{"beta", "3"}},
+			increment:       IncrementMinor,
+			preid:           "rc",
+			expectedVersion: "v1.4.0-rc.1",
+		},
+		{
+			name:            "lower identifier starts series of next release",
+			version:         Version{major: 1, minor: 2, patch: 0, prerelease: []string{"rc", "3"}},
+			increment:       IncrementMinor,
+			preid:           "beta",
+			expectedVersion: "v1.3.0-beta.1",
+		},
+		{
+			name:            "lower identifier with patch increment",
+			version:         Version{major: 1, minor: 2, patch: 0, prerelease: []string{"rc", "3"}},
+			increment:       IncrementPatch,
+			preid:           "beta",
+			expectedVersion: "v1.2.1-beta.1",
+		},
+	}
+
+	for _, testCase := range cases {
+		bumped := testCase.version.BumpPrerelease(testCase.increment, testCase.preid)
+
+		require.Equal(t, testCase.expectedVersion, bumped.String(), testCase.name)
+	}
+}
+
 func TestFormat(t *testing.T) {
 	cases := []struct {
 		version Version
@@ -54,6 +140,36 @@ func TestFormat(t *testing.T) {
 			format:          "v%major%.%minor%.%patch%-RC",
 			expectedVersion: "v1.2.3-RC",
 		},
+		{
+			version:         Version{major: 1, minor: 4, patch: 0, prerelease: []string{"rc", "1"}},
+			format:          "%major%.%minor%.%patch%",
+			expectedVersion: "1.4.0-rc.1",
+		},
+		{
+			version:         Version{major: 1, minor: 4, patch: 0, prerelease: []string{"rc", "1"}},
+			format:          "v%major%.%minor%.%patch%_%prerelease%",
+			expectedVersion: "v1.4.0_rc.1",
+		},
+		{
+			version:         Version{major: 1, minor: 2, patch: 3, build: []string{"sha", "abc1234"}},
+			format:          "v%major%.%minor%.%patch%",
+			expectedVersion: "v1.2.3+sha.abc1234",
+		},
+		{
+			version:         Version{major: 1, minor: 2, patch: 3, prerelease: []string{"rc", "1"}, build: []string{"456"}},
+			format:          "v%major%.%minor%.%patch%-%prerelease%",
+			expectedVersion: "v1.2.3-rc.1+456",
+		},
+		{
+			version:         Version{major: 1, minor: 2, patch: 3, prerelease: []string{"rc", "1"}},
+			format:          "v%major%.%minor%.%patch%+sha.abc1234",
+			expectedVersion: "v1.2.3-rc.1+sha.abc1234",
+		},
+		{
+			version:         Version{major: 1, minor: 2, patch: 3, build: []string{"456"}},
+			format:          "v%major%.%minor%.%patch%+sha.abc1234",
+			expectedVersion: "v1.2.3+sha.abc1234",
+		},
 	}
 
 	for _, testCase := range cases {
@@ -136,6 +252,16 @@ func TestParseVersion(t *testing.T) {
 			expectedVersion: Version{major: 1, minor: 2, patch: 0},
 			expectError:     false,
 		},
+		{
+			input:           "v1.4.0-rc.1",
+			expectedVersion: Version{major: 1, minor: 4, patch: 0, prerelease: []string{"rc", "1"}},
+			expectError:     false,
+		},
+		{
+			input:           "v1.2.3+sha.abc1234",
+			expectedVersion: Version{major: 1, minor: 2, patch: 3, build: []string{"sha", "abc1234"}},
+			expectError:     false,
+		},
 		{
 			input:           "vlala.2.3",
 			expectedVersion: Version{},
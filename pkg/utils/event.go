@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+)
+
+// The helpers below do not depend on the backend and are shared by all
+// GithubActionIface implementations.
+
+func parsePullRequestEvent(filePath string) (*github.PullRequestEvent, error) {
+	eventBytes, err := readGithubEvent(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := github.ParseWebHook("pull_request", eventBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	event, ok := parsed.(*github.PullRequestEvent)
+	if !ok {
+		return nil, fmt.Errorf("invalid event")
+	}
+	return event, nil
+
+}
+
+func parsePushEvent(filePath string) (*github.PushEvent, error) {
+	eventBytes, err := readGithubEvent(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := github.ParseWebHook("push", eventBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	event, ok := parsed.(*github.PushEvent)
+	if !ok {
+		return nil, fmt.Errorf("invalid event")
+	}
+	return event, nil
+}
+
+func nextTag(currentVersion, increment, format, prerelease string) (string, error) {
+	if prerelease != "" {
+		return semver.BumpSemverPrerelease(currentVersion, increment, prerelease, format)
+	}
+	return semver.BumpSemverVersion(currentVersion, increment, format)
+}
+
+func incrementFromEvent(eventPath string, mapping semver.LabelMapping) (semver.LabelResolution, error) {
+	event, err := parsePullRequestEvent(eventPath)
+	if err != nil {
+		return semver.LabelResolution{}, err
+	}
+	return semver.ResolvePullRequestLabels(event.PullRequest, mapping)
+}
+
+// labelExistsInEvent reports whether a label of the pull request matches
+// label, which may be a glob pattern.
+func labelExistsInEvent(label string, eventPath string) (bool, error) {
+	event, err := parsePullRequestEvent(eventPath)
+	if err != nil {
+		return false, err
+	}
+
+	for _, l := range event.PullRequest.Labels {
+		if semver.MatchLabel([]string{label}, l.GetName()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func readGithubEvent(filePath string) ([]byte, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	b, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
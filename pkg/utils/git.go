@@ -0,0 +1,319 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+)
+
+var ErrNotSupportedByGitBackend = errors.New("not supported by the git backend")
+
+// GitActionImpl implements GithubActionIface on top of a local git checkout
+// instead of the GitHub API, e.g. for mirrored repositories on self-hosted
+// runners or offline use of the command line.
+type GitActionImpl struct {
+	// Dir is the directory of the checkout.
+	Dir string
+	// Remote is the remote created tags are pushed to. Tags stay local when
+	// it is empty.
+	Remote string
+	// SigningFormat is the format of tag signatures, SigningFormatGPG or
+	// SigningFormatSSH.
+	SigningFormat string
+	// SigningKey signs tags: an armored private GPG key, a private SSH key,
+	// or the user.signingkey of a key the runner already has.
+	SigningKey string
+}
+
+func NewGitActionImpl(dir, remote string) (*GitActionImpl, error) {
+	impl := &GitActionImpl{
+		Dir:    dir,
+		Remote: remote,
+	}
+	if _, err := impl.git("rev-parse", "--git-dir"); err != nil {
+		return nil, err
+	}
+	return impl, nil
+}
+
+func (impl *GitActionImpl) ParseGithubEvent(filePath string) (*github.PullRequestEvent, error) {
+	return parsePullRequestEvent(filePath)
+}
+
+func (impl *GitActionImpl) ParseGithubPushEvent(filePath string) (*github.PushEvent, error) {
+	return parsePushEvent(filePath)
+}
+
+// GetGithubLatestTag returns the highest local tag written with tagFormat
+// whose version satisfies versionRange. When reachableFrom is set, only tags
+// that are ancestors of that commit are considered.
+func (impl *GitActionImpl) GetGithubLatestTag(versionRange, tagFormat, reachableFrom string) (string, error) {
+	args := []string{"tag", "--list"}
+	if reachableFrom != "" {
+		// git finds the reachable tags at once, without a check per tag
+		args = append(args, "--merged", reachableFrom)
+	}
+	out, err := impl.git(args...)
+	if err != nil {
+		return "", err
+	}
+	return latestTag(strings.Fields(out), versionRange, tagFormat, "", nil)
+}
+
+func (impl *GitActionImpl) GetNextTag(currentVersion, increment, format, prerelease string) (string, error) {
+	return nextTag(currentVersion, increment, format, prerelease)
+}
+
+// CreateGithubTag creates the tag in the checkout and pushes it to Remote.
+func (impl *GitActionImpl) CreateGithubTag(version, target string, options TagOptions) error {
+	if err := impl.tag(version, target, options); err != nil {
+		return err
+	}
+	if impl.Remote == "" {
+		return nil
+	}
+	_, err := impl.git("push", impl.Remote, "refs/tags/"+version)
+	if err == nil && options.Sign {
+		return impl.verifyPushedTag(version)
+	}
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		return err
+	}
+	// another run pushed the tag first, drop ours and take the tags of the
+	// remote so the latest tag is found again
+	if _, err := impl.git("tag", "--delete", version); err != nil {
+		return err
+	}
+	if _, err := impl.git("fetch", "--quiet", impl.Remote, "+refs/tags/*:refs/tags/*"); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s: %w", ErrTagExists, version, err)
+}
+
+// tag creates the tag in the checkout. The tagger of annotated tags is the
+// committer identity git tag uses, signed tags are signed with SigningKey.
+func (impl *GitActionImpl) tag(version, target string, options TagOptions) error {
+	if !options.Annotated {
+		_, err := impl.git("tag", version, target)
+		return err
+	}
+	var env []string
+	if options.TaggerName != "" {
+		env = append(env, "GIT_COMMITTER_NAME="+options.TaggerName)
+	}
+	if options.TaggerEmail != "" {
+		env = append(env, "GIT_COMMITTER_EMAIL="+options.TaggerEmail)
+	}
+	if !options.Date.IsZero() {
+		env = append(env, "GIT_COMMITTER_DATE="+options.Date.Format(time.RFC3339))
+	}
+	if options.Sign {
+		return impl.signTag(version, target, env, options)
+	}
+	_, err := impl.gitEnv(env, "tag", "--annotate", "--message", options.Message, version, target)
+	return err
+}
+
+func (impl *GitActionImpl) MoveGithubTag(version, target string) error {
+	if _, err := impl.git("tag", "--force", version, target); err != nil {
+		return err
+	}
+	if impl.Remote == "" {
+		return nil
+	}
+	_, err := impl.git("push", "--force", impl.Remote, "refs/tags/"+version)
+	return err
+}
+
+func (impl *GitActionImpl) CreateGithubRelease(version, target string, options ReleaseOptions) (*github.RepositoryRelease, error) {
+	return nil, fmt.Errorf("creating release %s: %w", version, ErrNotSupportedByGitBackend)
+}
+
+func (impl *GitActionImpl) GetGithubRelease(version string) (*github.RepositoryRelease, error) {
+	return nil, fmt.Errorf("getting release %s: %w", version, ErrNotSupportedByGitBackend)
+}
+
+func (impl *GitActionImpl) UploadReleaseAsset(releaseID int64, name, path string) (*github.ReleaseAsset, error) {
+	return nil, fmt.Errorf("uploading release asset %s: %w", name, ErrNotSupportedByGitBackend)
+}
+
+func (impl *GitActionImpl) ListReleaseAssets(releaseID int64) ([]*github.ReleaseAsset, error) {
+	return nil, fmt.Errorf("listing release assets: %w", ErrNotSupportedByGitBackend)
+}
+
+func (impl *GitActionImpl) DeleteReleaseAsset(assetID int64) error {
+	return fmt.Errorf("deleting release asset %d: %w", assetID, ErrNotSupportedByGitBackend)
+}
+
+func (impl *GitActionImpl) GenerateReleaseNotes(version, target, lastTag string) (*github.RepositoryReleaseNotes, *github.Response, error) {
+	return nil, nil, fmt.Errorf("generating release notes: %w", ErrNotSupportedByGitBackend)
+}
+
+func (impl *GitActionImpl) GetIncrementType(eventPath string, mapping semver.LabelMapping) (semver.LabelResolution, error) {
+	return incrementFromEvent(eventPath, mapping)
+}
+
+func (impl *GitActionImpl) DoesLabelExist(label string, eventPath string) (bool, error) {
+	return labelExistsInEvent(label, eventPath)
+}
+
+const (
+	gitFieldSeparator  = "\x1f"
+	gitRecordSeparator = "\x1e"
+)
+
+// ListCommits returns the commits reachable from head but not from base,
+// oldest first like the compare API.
+func (impl *GitActionImpl) ListCommits(base, head string) ([]*github.RepositoryCommit, error) {
+	return impl.log(base, head)
+}
+
+func (impl *GitActionImpl) ListPathCommits(base, head, path string) ([]*github.RepositoryCommit, error) {
+	return impl.log(base, head, "--", path)
+}
+
+// log returns the commits between base and head, oldest first, limited by
+// the git log arguments args.
+func (impl *GitActionImpl) log(base, head string, args ...string) ([]*github.RepositoryCommit, error) {
+	revisions := head
+	if base != "" {
+		revisions = base + ".." + head
+	}
+	out, err := impl.git(append([]string{"log", "--reverse", "--format=%H%x1f%an%x1f%ae%x1f%B%x1e", revisions}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*github.RepositoryCommit
+	for _, record := range strings.Split(out, gitRecordSeparator) {
+		fields := strings.SplitN(strings.TrimSpace(record), gitFieldSeparator, 4)
+		if len(fields) != 4 {
+			continue
+		}
+		commits = append(commits, &github.RepositoryCommit{
+			SHA: github.String(fields[0]),
+			Commit: &github.Commit{
+				SHA:     github.String(fields[0]),
+				Message: github.String(strings.TrimSpace(fields[3])),
+				Author: &github.CommitAuthor{
+					Name:  github.String(fields[1]),
+					Email: github.String(fields[2]),
+				},
+			},
+		})
+	}
+	return commits, nil
+}
+
+// ListChangedFiles returns the paths of the files changed between base and
+// head.
+func (impl *GitActionImpl) ListChangedFiles(base, head string) ([]string, error) {
+	out, err := impl.git("diff", "--name-only", "--no-renames", "-z", base, head)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, file := range strings.Split(out, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// ListPullRequestsWithCommit returns no pull requests, a checkout knows
+// nothing about them.
+func (impl *GitActionImpl) ListPullRequestsWithCommit(sha string) ([]*github.PullRequest, error) {
+	return nil, nil
+}
+
+// ResolveSHA returns the SHA of the commit ref points to.
+func (impl *GitActionImpl) ResolveSHA(ref string) (string, error) {
+	return impl.git("rev-parse", "--verify", ref+"^{commit}")
+}
+
+// GetTagSHA returns the SHA of the commit tag points to, peeling annotated
+// tags, or ErrTagNotFound.
+func (impl *GitActionImpl) GetTagSHA(tag string) (string, error) {
+	sha, err := impl.git("rev-parse", "--verify", "--quiet", "refs/tags/"+tag+"^{commit}")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return "", fmt.Errorf("%w: %s", ErrTagNotFound, tag)
+	}
+	return sha, err
+}
+
+// ReleaseExists reports no releases, a checkout knows nothing about them.
+func (impl *GitActionImpl) ReleaseExists(version string) (bool, error) {
+	return false, nil
+}
+
+// GetFileContent returns the content of the file at path and ref and the SHA
+// of its blob, or ErrFileNotFound.
+func (impl *GitActionImpl) GetFileContent(path, ref string) (string, string, error) {
+	object := ref + ":" + path
+	blobSHA, err := impl.git("rev-parse", "--verify", "--quiet", object)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return "", "", fmt.Errorf("%w: %s", ErrFileNotFound, path)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	content, err := impl.gitRaw("cat-file", "blob", blobSHA)
+	return content, blobSHA, err
+}
+
+func (impl *GitActionImpl) CommitFile(branch, path, content, message, blobSHA string) (string, error) {
+	return "", fmt.Errorf("committing %s: %w", path, ErrNotSupportedByGitBackend)
+}
+
+func (impl *GitActionImpl) CreateBranch(branch, sha string) error {
+	return fmt.Errorf("creating branch %s: %w", branch, ErrNotSupportedByGitBackend)
+}
+
+func (impl *GitActionImpl) BranchExists(branch string) (bool, error) {
+	return false, fmt.Errorf("looking up branch %s: %w", branch, ErrNotSupportedByGitBackend)
+}
+
+func (impl *GitActionImpl) CreatePullRequest(head, base, title, body string) (string, error) {
+	return "", fmt.Errorf("creating pull request: %w", ErrNotSupportedByGitBackend)
+}
+
+func (impl *GitActionImpl) GetOpenPullRequest(head, base string) (string, error) {
+	return "", fmt.Errorf("looking up pull request: %w", ErrNotSupportedByGitBackend)
+}
+
+func (impl *GitActionImpl) git(args ...string) (string, error) {
+	out, err := impl.gitRaw(args...)
+	return strings.TrimSpace(out), err
+}
+
+// gitRaw is git without trimming the output.
+func (impl *GitActionImpl) gitRaw(args ...string) (string, error) {
+	return impl.gitEnv(nil, args...)
+}
+
+// gitEnv runs git with env added to the environment and returns its
+// untrimmed output.
+func (impl *GitActionImpl) gitEnv(env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = impl.Dir
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRepository(t *testing.T) *GitActionImpl {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	run("init", "--quiet")
+	run("commit", "--quiet", "--allow-empty", "-m", "chore: initial commit")
+	run("tag", "v1.0.0")
+	run("tag", "release-2.0.0")
+	run("commit", "--quiet", "--allow-empty", "-m", "fix: handle empty input")
+	run("tag", "v1.0.1")
+	run("commit", "--quiet", "--allow-empty", "-m", "feat: add flag\n\nBREAKING CHANGE: flag is required")
+
+	impl, err := NewGitActionImpl(dir, "")
+	require.NoError(t, err)
+	return impl
+}
+
+func TestNewGitActionImplNotARepository(t *testing.T) {
+	_, err := NewGitActionImpl(t.TempDir(), "")
+	assert.Error(t, err)
+}
+
+func TestGitGetLatestTag(t *testing.T) {
+	impl := newTestRepository(t)
+
+	tag, err := impl.GetGithubLatestTag(">0.0.0", "v%major%.%minor%.%patch%", "")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.1", tag)
+
+	tag, err = impl.GetGithubLatestTag(">0.0.0", "release-%major%.%minor%.%patch%", "")
+	require.NoError(t, err)
+	assert.Equal(t, "release-2.0.0", tag)
+
+	_, err = impl.GetGithubLatestTag(">=3.0.0", "v%major%.%minor%.%patch%", "")
+	assert.Error(t, err)
+}
+
+func TestGitGetLatestTagReachable(t *testing.T) {
+	impl := newTestRepository(t)
+	head, err := impl.ResolveSHA("HEAD")
+	require.NoError(t, err)
+	for _, args := range [][]string{
+		{"checkout", "--quiet", "-b", "main", "v1.0.1"},
+		{"commit", "--quiet", "--allow-empty", "-m", "feat: only on main"},
+		{"tag", "v1.1.0"},
+	} {
+		_, err := impl.git(args...)
+		require.NoError(t, err)
+	}
+
+	tag, err := impl.GetGithubLatestTag(">0.0.0", "v%major%.%minor%.%patch%", "")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.1.0", tag)
+
+	tag, err = impl.GetGithubLatestTag(">0.0.0", "v%major%.%minor%.%patch%", head)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.1", tag)
+
+	_, err = impl.GetGithubLatestTag(">=1.1.0", "v%major%.%minor%.%patch%", head)
+	assert.ErrorIs(t, err, ErrNoMatchingTag)
+}
+
+func TestGitListCommits(t *testing.T) {
+	impl := newTestRepository(t)
+
+	commits, err := impl.ListCommits("v1.0.0", "HEAD")
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, "fix: handle empty input", commits[0].GetCommit().GetMessage())
+	assert.Equal(t, "feat: add flag\n\nBREAKING CHANGE: flag is required", commits[1].GetCommit().GetMessage())
+	assert.Equal(t, "Test", commits[1].GetCommit().GetAuthor().GetName())
+
+	commits, err = impl.ListCommits("", "HEAD")
+	require.NoError(t, err)
+	assert.Len(t, commits, 3)
+}
+
+func TestGitListPathCommits(t *testing.T) {
+	impl := newTestRepository(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(impl.Dir, "libs", "auth"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(impl.Dir, "libs", "auth", "auth.go"), []byte("package auth\n"), 0o644))
+	for _, args := range [][]string{
+		{"add", "."},
+		{"commit", "--quiet", "-m", "fix: add auth"},
+		{"commit", "--quiet", "--allow-empty", "-m", "feat: add api"},
+	} {
+		_, err := impl.git(args...)
+		require.NoError(t, err)
+	}
+
+	commits, err := impl.ListPathCommits("v1.0.1", "HEAD", "libs/auth")
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "fix: add auth", commits[0].GetCommit().GetMessage())
+
+	commits, err = impl.ListPathCommits("", "HEAD", "libs/auth")
+	require.NoError(t, err)
+	assert.Len(t, commits, 1)
+
+	commits, err = impl.ListPathCommits("v1.0.1", "HEAD", "libs/api")
+	require.NoError(t, err)
+	assert.Empty(t, commits)
+}
+
+func TestGitListChangedFiles(t *testing.T) {
+	impl := newTestRepository(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(impl.Dir, "libs", "auth"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(impl.Dir, "libs", "auth", "token file.go"), []byte("package auth\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(impl.Dir, "README.md"), []byte("# test\n"), 0o644))
+	for _, args := range [][]string{
+		{"add", "."},
+		{"commit", "--quiet", "-m", "feat: add auth"},
+	} {
+		_, err := impl.git(args...)
+		require.NoError(t, err)
+	}
+
+	files, err := impl.ListChangedFiles("v1.0.1", "HEAD")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"README.md", "libs/auth/token file.go"}, files)
+
+	files, err = impl.ListChangedFiles("v1.0.0", "v1.0.1")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestGitCreateTag(t *testing.T) {
+	impl := newTestRepository(t)
+	head, err := impl.ResolveSHA("HEAD")
+	require.NoError(t, err)
+
+	require.NoError(t, impl.CreateGithubTag("v1.1.0", head, TagOptions{}))
+	sha, err := impl.ResolveSHA("v1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, head, sha)
+	objectType, err := impl.git("cat-file", "-t", "v1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "commit", objectType)
+
+	require.NoError(t, impl.CreateGithubTag("v2.0.0", head, TagOptions{
+		Annotated:   true,
+		Message:     "Release v2.0.0",
+		TaggerName:  "Release Bot",
+		TaggerEmail: "release@example.com",
+		Date:        time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC),
+	}))
+	objectType, err = impl.git("cat-file", "-t", "v2.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "tag", objectType)
+	tagger, err := impl.git("for-each-ref", "--format=%(taggername) %(taggeremail) %(taggerdate:iso-strict) %(contents:subject)", "refs/tags/v2.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "Release Bot <release@example.com> 2024-10-01T12:00:00+00:00 Release v2.0.0", tagger)
+
+	assert.Error(t, impl.CreateGithubTag("v2.0.0", head, TagOptions{}))
+}
+
+func TestGitCreateTagPushRejected(t *testing.T) {
+	impl := newTestRepository(t)
+	head, err := impl.ResolveSHA("HEAD")
+	require.NoError(t, err)
+	tagged, err := impl.ResolveSHA("v1.0.1")
+	require.NoError(t, err)
+	remote := t.TempDir()
+	for _, args := range [][]string{
+		{"clone", "--quiet", "--bare", impl.Dir, remote},
+		{"--git-dir", remote, "tag", "v1.1.0", tagged},
+		{"remote", "add", "origin", remote},
+	} {
+		_, err := impl.git(args...)
+		require.NoError(t, err)
+	}
+	impl.Remote = "origin"
+
+	// a concurrent run pushed v1.1.0 first
+	assert.ErrorIs(t, impl.CreateGithubTag("v1.1.0", head, TagOptions{}), ErrTagExists)
+	sha, err := impl.GetTagSHA("v1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, tagged, sha)
+}
+
+func TestGitMoveTag(t *testing.T) {
+	impl := newTestRepository(t)
+	head, err := impl.ResolveSHA("HEAD")
+	require.NoError(t, err)
+	tagged, err := impl.ResolveSHA("v1.0.1")
+	require.NoError(t, err)
+
+	require.NoError(t, impl.MoveGithubTag("v1", tagged))
+	sha, err := impl.GetTagSHA("v1")
+	require.NoError(t, err)
+	assert.Equal(t, tagged, sha)
+
+	require.NoError(t, impl.MoveGithubTag("v1", head))
+	sha, err = impl.GetTagSHA("v1")
+	require.NoError(t, err)
+	assert.Equal(t, head, sha)
+}
+
+func TestGitGetTagSHA(t *testing.T) {
+	impl := newTestRepository(t)
+	head, err := impl.ResolveSHA("HEAD")
+	require.NoError(t, err)
+	tagged, err := impl.ResolveSHA("v1.0.1")
+	require.NoError(t, err)
+
+	sha, err := impl.GetTagSHA("v1.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, tagged, sha)
+
+	require.NoError(t, impl.CreateGithubTag("v2.0.0", head, TagOptions{Annotated: true, Message: "v2.0.0"}))
+	sha, err = impl.GetTagSHA("v2.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, head, sha)
+
+	_, err = impl.GetTagSHA("v3.0.0")
+	assert.ErrorIs(t, err, ErrTagNotFound)
+
+	released, err := impl.ReleaseExists("v2.0.0")
+	require.NoError(t, err)
+	assert.False(t, released)
+}
+
+func TestGitGetFileContent(t *testing.T) {
+	impl := newTestRepository(t)
+	require.NoError(t, os.WriteFile(filepath.Join(impl.Dir, "CHANGELOG.md"), []byte("# Changelog\n\n"), 0o644))
+	_, err := impl.git("add", "CHANGELOG.md")
+	require.NoError(t, err)
+	_, err = impl.git("commit", "--quiet", "-m", "docs: add changelog")
+	require.NoError(t, err)
+
+	content, blobSHA, err := impl.GetFileContent("CHANGELOG.md", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "# Changelog\n\n", content)
+	assert.NotEmpty(t, blobSHA)
+
+	_, _, err = impl.GetFileContent("CHANGELOG.md", "v1.0.1")
+	assert.ErrorIs(t, err, ErrFileNotFound)
+}
+
+func TestGitReleaseNotSupported(t *testing.T) {
+	impl := newTestRepository(t)
+	_, err := impl.CreateGithubRelease("v1.1.0", "HEAD", ReleaseOptions{})
+	assert.ErrorIs(t, err, ErrNotSupportedByGitBackend)
+	_, err = impl.UploadReleaseAsset(1, "app.tar.gz", "app.tar.gz")
+	assert.ErrorIs(t, err, ErrNotSupportedByGitBackend)
+	_, err = impl.CommitFile("main", "CHANGELOG.md", "", "docs: changelog", "")
+	assert.ErrorIs(t, err, ErrNotSupportedByGitBackend)
+	assert.ErrorIs(t, impl.CreateBranch("changelog", "HEAD"), ErrNotSupportedByGitBackend)
+	_, err = impl.CreatePullRequest("changelog", "main", "docs: changelog", "")
+	assert.ErrorIs(t, err, ErrNotSupportedByGitBackend)
+}
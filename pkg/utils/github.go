@@ -5,15 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/actions-go/toolkit/core"
-	blangsemver "github.com/blang/semver/v4"
-	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
 
 	"github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
 	"golang.org/x/oauth2"
 )
 
@@ -37,144 +40,577 @@ func NewGithubActionImpl(repository, token, githubApiUrl, githubUploadsUrl strin
 }
 
 func (impl *GithubActionImpl) ParseGithubEvent(filePath string) (*github.PullRequestEvent, error) {
-	eventBytes, err := readGithubEvent(filePath)
+	return parsePullRequestEvent(filePath)
+}
+
+func (impl *GithubActionImpl) ParseGithubPushEvent(filePath string) (*github.PushEvent, error) {
+	return parsePushEvent(filePath)
+}
+
+// GetGithubLatestTag returns the highest tag written with tagFormat whose
+// version satisfies versionRange. When reachableFrom is set, only tags that
+// are ancestors of that commit are considered.
+func (impl *GithubActionImpl) GetGithubLatestTag(versionRange, tagFormat, reachableFrom string) (string, error) {
+	tags, err := impl.listTags()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
+	return latestTag(tags, versionRange, tagFormat, reachableFrom, impl.isAncestor)
+}
 
-	parsed, err := github.ParseWebHook("pull_request", eventBytes)
+// listTags returns the names of all tags of the repository, following the
+// pagination of the matching refs API.
+func (impl *GithubActionImpl) listTags() ([]string, error) {
+	owner, repo, err := parseRepository(impl.Repository)
 	if err != nil {
 		return nil, err
 	}
 
-	event, ok := parsed.(*github.PullRequestEvent)
-	if !ok {
-		return nil, fmt.Errorf("invalid event")
+	var tags []string
+	opts := &github.ReferenceListOptions{
+		Ref:         "tags",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		refs, response, err := impl.GithubClient.Git.ListMatchingRefs(context.Background(), owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return nil, errors.New("wrong response when listing matching refs")
+		}
+		for _, ref := range refs {
+			tags = append(tags, strings.TrimPrefix(ref.GetRef(), "refs/tags/"))
+		}
+		if response == nil || response.NextPage == 0 {
+			return tags, nil
+		}
+		opts.Page = response.NextPage
 	}
-	return event, nil
-
 }
 
-func (impl *GithubActionImpl) GetGithubLatestTag(versionRange string) (string, error) {
+// isAncestor reports whether tag points to sha or one of its ancestors,
+// i.e. whether sha is ahead of or identical to tag.
+func (impl *GithubActionImpl) isAncestor(tag, sha string) (bool, error) {
 	owner, repo, err := parseRepository(impl.Repository)
 	if err != nil {
-		return "", err
+		return false, err
 	}
-	refs, response, err := impl.GithubClient.Git.ListMatchingRefs(context.Background(), owner, repo, &github.ReferenceListOptions{
-		Ref: "tags",
-	})
+	comparison, _, err := impl.GithubClient.Repositories.CompareCommits(context.Background(), owner, repo, tag, sha, &github.ListOptions{PerPage: 1})
 	if err != nil {
-		return "", err
-	}
-	if response != nil && response.StatusCode == http.StatusNotFound {
-		return "", errors.New("wrong response when listing matching refs")
+		return false, err
 	}
-	expectedRange, err := blangsemver.ParseRange(versionRange)
+	status := comparison.GetStatus()
+	return status == "ahead" || status == "identical", nil
+}
+
+func (impl *GithubActionImpl) GetNextTag(currentVersion, increment, format, prerelease string) (string, error) {
+	return nextTag(currentVersion, increment, format, prerelease)
+}
+
+// CreateGithubTag creates the ref of the tag version at target. Annotated
+// tags first create a tag object the ref points to.
+func (impl *GithubActionImpl) CreateGithubTag(version, target string, options TagOptions) error {
+	owner, repo, err := parseRepository(impl.Repository)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	latest := blangsemver.MustParse("0.0.0")
-	var latestTag string
-	for _, ref := range refs {
-		tag := strings.Replace(*ref.Ref, "refs/tags/", "", 1)
-		version, err := blangsemver.ParseTolerant(tag)
+	if options.Sign {
+		return fmt.Errorf("signing tag %s: %w", version, ErrSigningNotSupported)
+	}
+	object := &github.GitObject{SHA: &target}
+	if options.Annotated {
+		tag, _, err := impl.GithubClient.Git.CreateTag(context.Background(), owner, repo, &github.Tag{
+			Tag:     &version,
+			Message: &options.Message,
+			Object:  &github.GitObject{Type: github.String("commit"), SHA: &target},
+			Tagger:  tagger(options),
+		})
 		if err != nil {
-			continue
+			return err
 		}
-		if expectedRange(version) && version.GT(latest) {
-			latest = version
-			latestTag = tag // preserve the original tag (e.g., v1.2.3)
-		}
-	}
-	if latestTag == "" {
-		return "", errors.New("no matching tag found")
+		object = &github.GitObject{SHA: tag.SHA}
 	}
-	return latestTag, nil
+	_, _, err = impl.GithubClient.Git.CreateRef(context.Background(), owner, repo, &github.Reference{
+		Ref:    github.String(fmt.Sprintf("refs/tags/%s", version)),
+		Object: object,
+	})
+	return tagExistsError(version, err)
 }
 
-func (impl *GithubActionImpl) GetNextTag(currentVersion, increment, format string) (string, error) {
-	return semver.BumpSemverVersion(currentVersion, increment, format)
+// tagger returns the tagger of an annotated tag, nil to leave it to GitHub.
+func tagger(options TagOptions) *github.CommitAuthor {
+	if options.TaggerName == "" && options.TaggerEmail == "" {
+		return nil
+	}
+	date := options.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	return &github.CommitAuthor{
+		Name:  optionalString(options.TaggerName),
+		Email: optionalString(options.TaggerEmail),
+		Date:  &github.Timestamp{Time: date},
+	}
 }
 
-func (impl *GithubActionImpl) CreateGithubTag(version, target string) error {
+func (impl *GithubActionImpl) MoveGithubTag(version, target string) error {
+	err := impl.CreateGithubTag(version, target, TagOptions{})
+	if !errors.Is(err, ErrTagExists) {
+		return err
+	}
 	owner, repo, err := parseRepository(impl.Repository)
 	if err != nil {
 		return err
 	}
-
-	_, _, err = impl.GithubClient.Git.CreateRef(context.Background(), owner, repo, &github.Reference{
+	_, _, err = impl.GithubClient.Git.UpdateRef(context.Background(), owner, repo, &github.Reference{
 		Ref: github.String(fmt.Sprintf("refs/tags/%s", version)),
 		Object: &github.GitObject{
 			SHA: &target,
 		},
-	})
+	}, true)
 	return err
 }
 
-func (impl *GithubActionImpl) CreateGithubRelease(version, target string) error {
+func (impl *GithubActionImpl) CreateGithubRelease(version, target string, options ReleaseOptions) (*github.RepositoryRelease, error) {
 	owner, repo, err := parseRepository(impl.Repository)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	_, _, err = impl.GithubClient.Repositories.CreateRelease(context.Background(), owner, repo, &github.RepositoryRelease{
+	release, _, err := impl.GithubClient.Repositories.CreateRelease(context.Background(), owner, repo, &github.RepositoryRelease{
 		Name:                 &version,
 		TagName:              &version,
 		TargetCommitish:      &target,
-		Draft:                github.Bool(false),
-		Prerelease:           github.Bool(false),
-		GenerateReleaseNotes: github.Bool(true),
+		Draft:                github.Bool(options.Draft),
+		Prerelease:           github.Bool(options.Prerelease),
+		MakeLatest:           optionalString(options.MakeLatest),
+		Body:                 optionalString(options.Body),
+		GenerateReleaseNotes: github.Bool(options.Body == ""),
 	})
+	return release, tagExistsError(version, err)
+}
+
+// GetGithubRelease returns the release of the tag version, drafts included,
+// or ErrReleaseNotFound.
+func (impl *GithubActionImpl) GetGithubRelease(version string) (*github.RepositoryRelease, error) {
+	release, err := impl.findRelease(version)
+	if err == nil && release == nil {
+		return nil, fmt.Errorf("%w: %s", ErrReleaseNotFound, version)
+	}
+	return release, err
+}
+
+// findRelease returns the release of the tag version, or nil when there is
+// none. The releases API does not find drafts by tag, they are looked up in
+// the list of all releases.
+func (impl *GithubActionImpl) findRelease(version string) (*github.RepositoryRelease, error) {
+	owner, repo, err := parseRepository(impl.Repository)
+	if err != nil {
+		return nil, err
+	}
+	release, response, err := impl.GithubClient.Repositories.GetReleaseByTag(context.Background(), owner, repo, version)
+	if response == nil || response.StatusCode != http.StatusNotFound {
+		return release, err
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		releases, response, err := impl.GithubClient.Repositories.ListReleases(context.Background(), owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, release := range releases {
+			if release.GetTagName() == version {
+				return release, nil
+			}
+		}
+		if response == nil || response.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = response.NextPage
+	}
+}
+
+func (impl *GithubActionImpl) UploadReleaseAsset(releaseID int64, name, path string) (*github.ReleaseAsset, error) {
+	owner, repo, err := parseRepository(impl.Repository)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	mediaType, err := detectContentType(file)
+	if err != nil {
+		return nil, err
+	}
+	asset, _, err := impl.GithubClient.Repositories.UploadReleaseAsset(context.Background(), owner, repo, releaseID, &github.UploadOptions{
+		Name:      name,
+		MediaType: mediaType,
+	}, file)
+	return asset, err
+}
+
+// detectContentType returns the content type of file from its extension or,
+// for unknown extensions, from its first bytes. The file is read from the
+// start again afterwards.
+func detectContentType(file *os.File) (string, error) {
+	if mediaType := mime.TypeByExtension(filepath.Ext(file.Name())); mediaType != "" {
+		return mediaType, nil
+	}
+	head := make([]byte, 512)
+	n, err := file.Read(head)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+// ListReleaseAssets returns all assets of the release with the ID releaseID,
+// following the pagination of the API.
+func (impl *GithubActionImpl) ListReleaseAssets(releaseID int64) ([]*github.ReleaseAsset, error) {
+	owner, repo, err := parseRepository(impl.Repository)
+	if err != nil {
+		return nil, err
+	}
+	var assets []*github.ReleaseAsset
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, response, err := impl.GithubClient.Repositories.ListReleaseAssets(context.Background(), owner, repo, releaseID, opts)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, page...)
+		if response.NextPage == 0 {
+			return assets, nil
+		}
+		opts.Page = response.NextPage
+	}
+}
+
+func (impl *GithubActionImpl) DeleteReleaseAsset(assetID int64) error {
+	owner, repo, err := parseRepository(impl.Repository)
+	if err != nil {
+		return err
+	}
+	_, err = impl.GithubClient.Repositories.DeleteReleaseAsset(context.Background(), owner, repo, assetID)
 	return err
 }
 
-func (impl *GithubActionImpl) GenerateReleaseNotes(version, lastTag string) (*github.RepositoryReleaseNotes, *github.Response, error) {
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// tagExistsError wraps err with ErrTagExists when the API rejected creating
+// the tag or release of version because it already exists.
+func tagExistsError(version string, err error) error {
+	var errorResponse *github.ErrorResponse
+	if !errors.As(err, &errorResponse) || errorResponse.Response == nil || errorResponse.Response.StatusCode != http.StatusUnprocessableEntity {
+		return err
+	}
+	exists := strings.Contains(errorResponse.Message, "already exists")
+	for _, e := range errorResponse.Errors {
+		exists = exists || e.Code == "already_exists"
+	}
+	if !exists {
+		return err
+	}
+	return fmt.Errorf("%w: %s: %w", ErrTagExists, version, err)
+}
+
+func (impl *GithubActionImpl) GenerateReleaseNotes(version, target, lastTag string) (*github.RepositoryReleaseNotes, *github.Response, error) {
 	core.Debug("Last tag: " + lastTag)
 	core.Debug("Version: " + version)
 	owner, repo, err := parseRepository(impl.Repository)
 	if err != nil {
 		return nil, nil, err
 	}
-	return impl.GithubClient.Repositories.GenerateReleaseNotes(context.Background(), owner, repo, &github.GenerateNotesOptions{
-		TagName:         version,
-		PreviousTagName: github.String(lastTag),
-	})
+	opts := &github.GenerateNotesOptions{TagName: version, TargetCommitish: github.String(target)}
+	if lastTag != "" {
+		// without it GitHub starts at the previous release, e.g. for the
+		// first release of a component
+		opts.PreviousTagName = github.String(lastTag)
+	}
+	return impl.GithubClient.Repositories.GenerateReleaseNotes(context.Background(), owner, repo, opts)
 }
 
-func (impl *GithubActionImpl) GetIncrementType(eventPath string) (string, error) {
-	event, err := impl.ParseGithubEvent(eventPath)
+func (impl *GithubActionImpl) GetIncrementType(eventPath string, mapping semver.LabelMapping) (semver.LabelResolution, error) {
+	return incrementFromEvent(eventPath, mapping)
+}
+
+// ListCommits returns the commits reachable from head but not from base.
+func (impl *GithubActionImpl) ListCommits(base, head string) ([]*github.RepositoryCommit, error) {
+	owner, repo, err := parseRepository(impl.Repository)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	var commits []*github.RepositoryCommit
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		comparison, response, err := impl.GithubClient.Repositories.CompareCommits(context.Background(), owner, repo, base, head, opts)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, comparison.Commits...)
+		if response.NextPage == 0 {
+			return commits, nil
+		}
+		opts.Page = response.NextPage
 	}
-	increment, err := semver.ExtractSemVerIncrementFromPullRequest(event.PullRequest)
-	return string(increment), err
 }
 
-func (impl *GithubActionImpl) DoesLabelExist(label string, eventPath string) (bool, error) {
-	event, err := impl.ParseGithubEvent(eventPath)
+// ListPathCommits lists the commits of head changing files below path, which
+// the compare API cannot filter. Commits of the range between base and head
+// are not older than its oldest commit, so the history of path is only read
+// back to that one.
+func (impl *GithubActionImpl) ListPathCommits(base, head, path string) ([]*github.RepositoryCommit, error) {
+	owner, repo, err := parseRepository(impl.Repository)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	for _, l := range event.PullRequest.Labels {
-		if strings.EqualFold(*l.Name, label) {
-			return true, nil
+	opts := &github.CommitsListOptions{SHA: head, Path: path, ListOptions: github.ListOptions{PerPage: 100}}
+	var inRange map[string]bool
+	if base != "" {
+		commits, err := impl.ListCommits(base, head)
+		if err != nil || len(commits) == 0 {
+			return nil, err
+		}
+		inRange = map[string]bool{}
+		for _, commit := range commits {
+			inRange[commit.GetSHA()] = true
+			date := commit.GetCommit().GetCommitter().GetDate().Time
+			if opts.Since.IsZero() || date.Before(opts.Since) {
+				opts.Since = date
+			}
 		}
 	}
-	return false, nil
+
+	var commits []*github.RepositoryCommit
+	for {
+		page, response, err := impl.GithubClient.Repositories.ListCommits(context.Background(), owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, commit := range page {
+			if inRange == nil || inRange[commit.GetSHA()] {
+				commits = append(commits, commit)
+			}
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		opts.Page = response.NextPage
+	}
+	// oldest first, like the compare API
+	slices.Reverse(commits)
+	return commits, nil
 }
 
-func readGithubEvent(filePath string) ([]byte, error) {
-	file, err := os.Open(filePath)
+// ListChangedFiles returns the paths of the files changed between base and
+// head.
+func (impl *GithubActionImpl) ListChangedFiles(base, head string) ([]string, error) {
+	owner, repo, err := parseRepository(impl.Repository)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-	b, err := io.ReadAll(file)
+
+	var files []string
+	seen := map[string]bool{}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		comparison, response, err := impl.GithubClient.Repositories.CompareCommits(context.Background(), owner, repo, base, head, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range comparison.Files {
+			// renamed files changed both paths
+			for _, path := range []string{file.GetFilename(), file.GetPreviousFilename()} {
+				if path != "" && !seen[path] {
+					seen[path] = true
+					files = append(files, path)
+				}
+			}
+		}
+		if response.NextPage == 0 {
+			return files, nil
+		}
+		opts.Page = response.NextPage
+	}
+}
+
+// ListPullRequestsWithCommit returns the pull requests containing the commit
+// sha, e.g. the pull request a pushed merge commit comes from.
+func (impl *GithubActionImpl) ListPullRequestsWithCommit(sha string) ([]*github.PullRequest, error) {
+	owner, repo, err := parseRepository(impl.Repository)
 	if err != nil {
 		return nil, err
 	}
-	return b, nil
+	pullRequests, _, err := impl.GithubClient.PullRequests.ListPullRequestsWithCommit(context.Background(), owner, repo, sha, &github.ListOptions{PerPage: 100})
+	return pullRequests, err
+}
+
+func (impl *GithubActionImpl) DoesLabelExist(label string, eventPath string) (bool, error) {
+	return labelExistsInEvent(label, eventPath)
+}
+
+// ResolveSHA returns the SHA of the commit ref points to.
+func (impl *GithubActionImpl) ResolveSHA(ref string) (string, error) {
+	owner, repo, err := parseRepository(impl.Repository)
+	if err != nil {
+		return "", err
+	}
+	sha, _, err := impl.GithubClient.Repositories.GetCommitSHA1(context.Background(), owner, repo, ref, "")
+	return sha, err
+}
+
+// GetTagSHA returns the SHA of the commit tag points to, peeling annotated
+// tags, or ErrTagNotFound.
+func (impl *GithubActionImpl) GetTagSHA(tag string) (string, error) {
+	owner, repo, err := parseRepository(impl.Repository)
+	if err != nil {
+		return "", err
+	}
+	ref, response, err := impl.GithubClient.Git.GetRef(context.Background(), owner, repo, "tags/"+tag)
+	if response != nil && response.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%w: %s", ErrTagNotFound, tag)
+	}
+	if err != nil {
+		return "", err
+	}
+	if ref.GetObject().GetType() != "tag" {
+		return ref.GetObject().GetSHA(), nil
+	}
+	tagObject, _, err := impl.GithubClient.Git.GetTag(context.Background(), owner, repo, ref.GetObject().GetSHA())
+	if err != nil {
+		return "", err
+	}
+	return tagObject.GetObject().GetSHA(), nil
+}
+
+// ReleaseExists reports whether a release of the tag version exists, drafts
+// included.
+func (impl *GithubActionImpl) ReleaseExists(version string) (bool, error) {
+	release, err := impl.findRelease(version)
+	return release != nil, err
+}
+
+// GetFileContent returns the content of the file at path and ref and the SHA
+// of its blob, or ErrFileNotFound.
+func (impl *GithubActionImpl) GetFileContent(path, ref string) (string, string, error) {
+	owner, repo, err := parseRepository(impl.Repository)
+	if err != nil {
+		return "", "", err
+	}
+	file, _, response, err := impl.GithubClient.Repositories.GetContents(context.Background(), owner, repo, path, &github.RepositoryContentGetOptions{Ref: ref})
+	if response != nil && response.StatusCode == http.StatusNotFound {
+		return "", "", fmt.Errorf("%w: %s", ErrFileNotFound, path)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if file == nil {
+		return "", "", fmt.Errorf("%s is a directory", path)
+	}
+	content, err := file.GetContent()
+	return content, file.GetSHA(), err
+}
+
+// CommitFile commits content to the file at path on branch through the
+// contents API and returns the SHA of the commit.
+func (impl *GithubActionImpl) CommitFile(branch, path, content, message, blobSHA string) (string, error) {
+	owner, repo, err := parseRepository(impl.Repository)
+	if err != nil {
+		return "", err
+	}
+	options := &github.RepositoryContentFileOptions{
+		Message: &message,
+		Content: []byte(content),
+		Branch:  &branch,
+		SHA:     optionalString(blobSHA),
+	}
+	var response *github.RepositoryContentResponse
+	if blobSHA == "" {
+		response, _, err = impl.GithubClient.Repositories.CreateFile(context.Background(), owner, repo, path, options)
+	} else {
+		response, _, err = impl.GithubClient.Repositories.UpdateFile(context.Background(), owner, repo, path, options)
+	}
+	if err != nil {
+		return "", err
+	}
+	return response.Commit.GetSHA(), nil
+}
+
+// CreateBranch creates branch at sha.
+func (impl *GithubActionImpl) CreateBranch(branch, sha string) error {
+	owner, repo, err := parseRepository(impl.Repository)
+	if err != nil {
+		return err
+	}
+	_, _, err = impl.GithubClient.Git.CreateRef(context.Background(), owner, repo, &github.Reference{
+		Ref:    github.String("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: &sha},
+	})
+	return err
+}
+
+// BranchExists reports whether branch exists.
+func (impl *GithubActionImpl) BranchExists(branch string) (bool, error) {
+	owner, repo, err := parseRepository(impl.Repository)
+	if err != nil {
+		return false, err
+	}
+	_, response, err := impl.GithubClient.Git.GetRef(context.Background(), owner, repo, "heads/"+branch)
+	if response != nil && response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// CreatePullRequest opens a pull request of head into base and returns its
+// URL.
+func (impl *GithubActionImpl) CreatePullRequest(head, base, title, body string) (string, error) {
+	owner, repo, err := parseRepository(impl.Repository)
+	if err != nil {
+		return "", err
+	}
+	pullRequest, _, err := impl.GithubClient.PullRequests.Create(context.Background(), owner, repo, &github.NewPullRequest{
+		Title: &title,
+		Head:  &head,
+		Base:  &base,
+		Body:  &body,
+	})
+	if err != nil {
+		return "", err
+	}
+	return pullRequest.GetHTMLURL(), nil
+}
+
+// GetOpenPullRequest returns the URL of the open pull request of head into
+// base, empty when there is none.
+func (impl *GithubActionImpl) GetOpenPullRequest(head, base string) (string, error) {
+	owner, repo, err := parseRepository(impl.Repository)
+	if err != nil {
+		return "", err
+	}
+	pullRequests, _, err := impl.GithubClient.PullRequests.List(context.Background(), owner, repo, &github.PullRequestListOptions{
+		State: "open",
+		Head:  owner + ":" + head,
+		Base:  base,
+	})
+	if err != nil || len(pullRequests) == 0 {
+		return "", err
+	}
+	return pullRequests[0].GetHTMLURL(), nil
 }
 
 func newGithubClient(ctx context.Context, token, githubApiUrl, githubUploadUrl string) (*github.Client, error) {
@@ -1,15 +1,96 @@
 package utils
 
-import "github.com/google/go-github/v65/github"
+import (
+	"errors"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+)
+
+var (
+	ErrFileNotFound    = errors.New("file not found")
+	ErrReleaseNotFound = errors.New("release not found")
+)
+
+// ReleaseOptions are the settings of a created release.
+type ReleaseOptions struct {
+	// Draft releases are not published, their tag has to exist already.
+	Draft      bool `json:"draft"`
+	Prerelease bool `json:"prerelease"`
+	// MakeLatest is "true", "false" or "legacy", the GitHub default when
+	// empty.
+	MakeLatest string `json:"make_latest,omitempty"`
+	// Body is the description of the release, GitHub generates release
+	// notes when empty.
+	Body string `json:"-"`
+	// Tag holds the options of the tag of the release.
+	Tag TagOptions `json:"-"`
+}
+
+// TagOptions are the options of a created tag.
+type TagOptions struct {
+	// Annotated creates a tag object carrying Message, the tagger and Date
+	// instead of a lightweight tag.
+	Annotated bool
+	Message   string
+	// TaggerName and TaggerEmail identify the tagger. The github backend
+	// leaves the tagger to GitHub and the git backend uses the identity of
+	// the checkout when they are empty.
+	TaggerName  string
+	TaggerEmail string
+	// Date is the date of the tag, now when zero.
+	Date time.Time
+	// Sign signs annotated tags, git backend only.
+	Sign bool
+}
 
 //go:generate mockgen -source=github_interface.go -destination=github_mock.go -package=utils
 type GithubActionIface interface {
-	CreateGithubTag(version, target string) error
-	CreateGithubRelease(version, target string) error
-	GenerateReleaseNotes(version, lastTag string) (*github.RepositoryReleaseNotes, *github.Response, error)
-	GetGithubLatestTag(versionRange string) (string, error)
+	CreateGithubTag(version, target string, options TagOptions) error
+	// MoveGithubTag creates the lightweight tag version at target or moves
+	// it there when it exists.
+	MoveGithubTag(version, target string) error
+	CreateGithubRelease(version, target string, options ReleaseOptions) (*github.RepositoryRelease, error)
+	// GenerateReleaseNotes generates the notes of the release of version,
+	// whose tag is created at target if it does not exist, since lastTag.
+	GenerateReleaseNotes(version, target, lastTag string) (*github.RepositoryReleaseNotes, *github.Response, error)
+	GetGithubLatestTag(versionRange, tagFormat, reachableFrom string) (string, error)
 	ParseGithubEvent(filePath string) (*github.PullRequestEvent, error)
-	GetIncrementType(eventPath string) (string, error)
-	GetNextTag(currentVersion, increment, format string) (string, error)
+	ParseGithubPushEvent(filePath string) (*github.PushEvent, error)
+	// GetIncrementType resolves the increment from the labels of the pull
+	// request of the event at eventPath.
+	GetIncrementType(eventPath string, mapping semver.LabelMapping) (semver.LabelResolution, error)
+	GetNextTag(currentVersion, increment, format, prerelease string) (string, error)
 	DoesLabelExist(label, eventPath string) (bool, error)
+	ListCommits(base, head string) ([]*github.RepositoryCommit, error)
+	// ListPathCommits is ListCommits for the commits changing files below
+	// path. An empty base lists all such commits of head.
+	ListPathCommits(base, head, path string) ([]*github.RepositoryCommit, error)
+	ListChangedFiles(base, head string) ([]string, error)
+	ListPullRequestsWithCommit(sha string) ([]*github.PullRequest, error)
+	ResolveSHA(ref string) (string, error)
+	GetTagSHA(tag string) (string, error)
+	ReleaseExists(version string) (bool, error)
+	GetGithubRelease(version string) (*github.RepositoryRelease, error)
+	// UploadReleaseAsset uploads the file at path as the asset name of the
+	// release with the ID releaseID, with a content type detected from the
+	// file.
+	UploadReleaseAsset(releaseID int64, name, path string) (*github.ReleaseAsset, error)
+	ListReleaseAssets(releaseID int64) ([]*github.ReleaseAsset, error)
+	DeleteReleaseAsset(assetID int64) error
+	// GetFileContent returns the content of the file at path and ref and the
+	// SHA of its blob, or ErrFileNotFound.
+	GetFileContent(path, ref string) (string, string, error)
+	// CommitFile commits content to the file at path on branch and returns
+	// the SHA of the commit. blobSHA is the SHA of the replaced blob, empty
+	// for new files.
+	CommitFile(branch, path, content, message, blobSHA string) (string, error)
+	CreateBranch(branch, sha string) error
+	BranchExists(branch string) (bool, error)
+	// CreatePullRequest opens a pull request and returns its URL.
+	CreatePullRequest(head, base, title, body string) (string, error)
+	// GetOpenPullRequest returns the URL of the open pull request of head
+	// into base, empty when there is none.
+	GetOpenPullRequest(head, base string) (string, error)
 }
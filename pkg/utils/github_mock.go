@@ -9,6 +9,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	github "github.com/google/go-github/v65/github"
+	semver "github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
 )
 
 // MockGithubActionIface is a mock of GithubActionIface interface.
@@ -34,32 +35,106 @@ func (m *MockGithubActionIface) EXPECT() *MockGithubActionIfaceMockRecorder {
 	return m.recorder
 }
 
-// CreateGithubRelease mocks base method.
-func (m *MockGithubActionIface) CreateGithubRelease(version, target string) error {
+// BranchExists mocks base method.
+func (m *MockGithubActionIface) BranchExists(branch string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateGithubRelease", version, target)
+	ret := m.ctrl.Call(m, "BranchExists", branch)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BranchExists indicates an expected call of BranchExists.
+func (mr *MockGithubActionIfaceMockRecorder) BranchExists(branch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BranchExists", reflect.TypeOf((*MockGithubActionIface)(nil).BranchExists), branch)
+}
+
+// CommitFile mocks base method.
+func (m *MockGithubActionIface) CommitFile(branch, path, content, message, blobSHA string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitFile", branch, path, content, message, blobSHA)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitFile indicates an expected call of CommitFile.
+func (mr *MockGithubActionIfaceMockRecorder) CommitFile(branch, path, content, message, blobSHA interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitFile", reflect.TypeOf((*MockGithubActionIface)(nil).CommitFile), branch, path, content, message, blobSHA)
+}
+
+// CreateBranch mocks base method.
+func (m *MockGithubActionIface) CreateBranch(branch, sha string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBranch", branch, sha)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// CreateBranch indicates an expected call of CreateBranch.
+func (mr *MockGithubActionIfaceMockRecorder) CreateBranch(branch, sha interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBranch", reflect.TypeOf((*MockGithubActionIface)(nil).CreateBranch), branch, sha)
+}
+
+// CreateGithubRelease mocks base method.
+func (m *MockGithubActionIface) CreateGithubRelease(version, target string, options ReleaseOptions) (*github.RepositoryRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGithubRelease", version, target, options)
+	ret0, _ := ret[0].(*github.RepositoryRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // CreateGithubRelease indicates an expected call of CreateGithubRelease.
-func (mr *MockGithubActionIfaceMockRecorder) CreateGithubRelease(version, target interface{}) *gomock.Call {
+func (mr *MockGithubActionIfaceMockRecorder) CreateGithubRelease(version, target, options interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGithubRelease", reflect.TypeOf((*MockGithubActionIface)(nil).CreateGithubRelease), version, target)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGithubRelease", reflect.TypeOf((*MockGithubActionIface)(nil).CreateGithubRelease), version, target, options)
 }
 
 // CreateGithubTag mocks base method.
-func (m *MockGithubActionIface) CreateGithubTag(version, target string) error {
+func (m *MockGithubActionIface) CreateGithubTag(version, target string, options TagOptions) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateGithubTag", version, target)
+	ret := m.ctrl.Call(m, "CreateGithubTag", version, target, options)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateGithubTag indicates an expected call of CreateGithubTag.
-func (mr *MockGithubActionIfaceMockRecorder) CreateGithubTag(version, target interface{}) *gomock.Call {
+func (mr *MockGithubActionIfaceMockRecorder) CreateGithubTag(version, target, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGithubTag", reflect.TypeOf((*MockGithubActionIface)(nil).CreateGithubTag), version, target, options)
+}
+
+// CreatePullRequest mocks base method.
+func (m *MockGithubActionIface) CreatePullRequest(head, base, title, body string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePullRequest", head, base, title, body)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePullRequest indicates an expected call of CreatePullRequest.
+func (mr *MockGithubActionIfaceMockRecorder) CreatePullRequest(head, base, title, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePullRequest", reflect.TypeOf((*MockGithubActionIface)(nil).CreatePullRequest), head, base, title, body)
+}
+
+// DeleteReleaseAsset mocks base method.
+func (m *MockGithubActionIface) DeleteReleaseAsset(assetID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReleaseAsset", assetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReleaseAsset indicates an expected call of DeleteReleaseAsset.
+func (mr *MockGithubActionIfaceMockRecorder) DeleteReleaseAsset(assetID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGithubTag", reflect.TypeOf((*MockGithubActionIface)(nil).CreateGithubTag), version, target)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReleaseAsset", reflect.TypeOf((*MockGithubActionIface)(nil).DeleteReleaseAsset), assetID)
 }
 
 // DoesLabelExist mocks base method.
@@ -78,9 +153,9 @@ func (mr *MockGithubActionIfaceMockRecorder) DoesLabelExist(label, eventPath int
 }
 
 // GenerateReleaseNotes mocks base method.
-func (m *MockGithubActionIface) GenerateReleaseNotes(version, lastTag string) (*github.RepositoryReleaseNotes, *github.Response, error) {
+func (m *MockGithubActionIface) GenerateReleaseNotes(version, target, lastTag string) (*github.RepositoryReleaseNotes, *github.Response, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateReleaseNotes", version, lastTag)
+	ret := m.ctrl.Call(m, "GenerateReleaseNotes", version, target, lastTag)
 	ret0, _ := ret[0].(*github.RepositoryReleaseNotes)
 	ret1, _ := ret[1].(*github.Response)
 	ret2, _ := ret[2].(error)
@@ -88,54 +163,204 @@ func (m *MockGithubActionIface) GenerateReleaseNotes(version, lastTag string) (*
 }
 
 // GenerateReleaseNotes indicates an expected call of GenerateReleaseNotes.
-func (mr *MockGithubActionIfaceMockRecorder) GenerateReleaseNotes(version, lastTag interface{}) *gomock.Call {
+func (mr *MockGithubActionIfaceMockRecorder) GenerateReleaseNotes(version, target, lastTag interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateReleaseNotes", reflect.TypeOf((*MockGithubActionIface)(nil).GenerateReleaseNotes), version, lastTag)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateReleaseNotes", reflect.TypeOf((*MockGithubActionIface)(nil).GenerateReleaseNotes), version, target, lastTag)
+}
+
+// GetFileContent mocks base method.
+func (m *MockGithubActionIface) GetFileContent(path, ref string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileContent", path, ref)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFileContent indicates an expected call of GetFileContent.
+func (mr *MockGithubActionIfaceMockRecorder) GetFileContent(path, ref interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileContent", reflect.TypeOf((*MockGithubActionIface)(nil).GetFileContent), path, ref)
 }
 
 // GetGithubLatestTag mocks base method.
-func (m *MockGithubActionIface) GetGithubLatestTag(versionRange string) (string, error) {
+func (m *MockGithubActionIface) GetGithubLatestTag(versionRange, tagFormat, reachableFrom string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGithubLatestTag", versionRange)
+	ret := m.ctrl.Call(m, "GetGithubLatestTag", versionRange, tagFormat, reachableFrom)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetGithubLatestTag indicates an expected call of GetGithubLatestTag.
-func (mr *MockGithubActionIfaceMockRecorder) GetGithubLatestTag(versionRange interface{}) *gomock.Call {
+func (mr *MockGithubActionIfaceMockRecorder) GetGithubLatestTag(versionRange, tagFormat, reachableFrom interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGithubLatestTag", reflect.TypeOf((*MockGithubActionIface)(nil).GetGithubLatestTag), versionRange, tagFormat, reachableFrom)
+}
+
+// GetGithubRelease mocks base method.
+func (m *MockGithubActionIface) GetGithubRelease(version string) (*github.RepositoryRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGithubRelease", version)
+	ret0, _ := ret[0].(*github.RepositoryRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGithubRelease indicates an expected call of GetGithubRelease.
+func (mr *MockGithubActionIfaceMockRecorder) GetGithubRelease(version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGithubLatestTag", reflect.TypeOf((*MockGithubActionIface)(nil).GetGithubLatestTag), versionRange)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGithubRelease", reflect.TypeOf((*MockGithubActionIface)(nil).GetGithubRelease), version)
 }
 
 // GetIncrementType mocks base method.
-func (m *MockGithubActionIface) GetIncrementType(eventPath string) (string, error) {
+func (m *MockGithubActionIface) GetIncrementType(eventPath string, mapping semver.LabelMapping) (semver.LabelResolution, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetIncrementType", eventPath)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetIncrementType", eventPath, mapping)
+	ret0, _ := ret[0].(semver.LabelResolution)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetIncrementType indicates an expected call of GetIncrementType.
-func (mr *MockGithubActionIfaceMockRecorder) GetIncrementType(eventPath interface{}) *gomock.Call {
+func (mr *MockGithubActionIfaceMockRecorder) GetIncrementType(eventPath, mapping interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncrementType", reflect.TypeOf((*MockGithubActionIface)(nil).GetIncrementType), eventPath)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncrementType", reflect.TypeOf((*MockGithubActionIface)(nil).GetIncrementType), eventPath, mapping)
 }
 
 // GetNextTag mocks base method.
-func (m *MockGithubActionIface) GetNextTag(currentVersion, increment, format string) (string, error) {
+func (m *MockGithubActionIface) GetNextTag(currentVersion, increment, format, prerelease string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNextTag", currentVersion, increment, format)
+	ret := m.ctrl.Call(m, "GetNextTag", currentVersion, increment, format, prerelease)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetNextTag indicates an expected call of GetNextTag.
-func (mr *MockGithubActionIfaceMockRecorder) GetNextTag(currentVersion, increment, format interface{}) *gomock.Call {
+func (mr *MockGithubActionIfaceMockRecorder) GetNextTag(currentVersion, increment, format, prerelease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextTag", reflect.TypeOf((*MockGithubActionIface)(nil).GetNextTag), currentVersion, increment, format, prerelease)
+}
+
+// GetOpenPullRequest mocks base method.
+func (m *MockGithubActionIface) GetOpenPullRequest(head, base string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenPullRequest", head, base)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenPullRequest indicates an expected call of GetOpenPullRequest.
+func (mr *MockGithubActionIfaceMockRecorder) GetOpenPullRequest(head, base interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextTag", reflect.TypeOf((*MockGithubActionIface)(nil).GetNextTag), currentVersion, increment, format)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenPullRequest", reflect.TypeOf((*MockGithubActionIface)(nil).GetOpenPullRequest), head, base)
+}
+
+// GetTagSHA mocks base method.
+func (m *MockGithubActionIface) GetTagSHA(tag string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagSHA", tag)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagSHA indicates an expected call of GetTagSHA.
+func (mr *MockGithubActionIfaceMockRecorder) GetTagSHA(tag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagSHA", reflect.TypeOf((*MockGithubActionIface)(nil).GetTagSHA), tag)
+}
+
+// ListChangedFiles mocks base method.
+func (m *MockGithubActionIface) ListChangedFiles(base, head string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChangedFiles", base, head)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChangedFiles indicates an expected call of ListChangedFiles.
+func (mr *MockGithubActionIfaceMockRecorder) ListChangedFiles(base, head interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChangedFiles", reflect.TypeOf((*MockGithubActionIface)(nil).ListChangedFiles), base, head)
+}
+
+// ListCommits mocks base method.
+func (m *MockGithubActionIface) ListCommits(base, head string) ([]*github.RepositoryCommit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCommits", base, head)
+	ret0, _ := ret[0].([]*github.RepositoryCommit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCommits indicates an expected call of ListCommits.
+func (mr *MockGithubActionIfaceMockRecorder) ListCommits(base, head interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommits", reflect.TypeOf((*MockGithubActionIface)(nil).ListCommits), base, head)
+}
+
+// ListPathCommits mocks base method.
+func (m *MockGithubActionIface) ListPathCommits(base, head, path string) ([]*github.RepositoryCommit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPathCommits", base, head, path)
+	ret0, _ := ret[0].([]*github.RepositoryCommit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPathCommits indicates an expected call of ListPathCommits.
+func (mr *MockGithubActionIfaceMockRecorder) ListPathCommits(base, head, path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPathCommits", reflect.TypeOf((*MockGithubActionIface)(nil).ListPathCommits), base, head, path)
+}
+
+// ListPullRequestsWithCommit mocks base method.
+func (m *MockGithubActionIface) ListPullRequestsWithCommit(sha string) ([]*github.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPullRequestsWithCommit", sha)
+	ret0, _ := ret[0].([]*github.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPullRequestsWithCommit indicates an expected call of ListPullRequestsWithCommit.
+func (mr *MockGithubActionIfaceMockRecorder) ListPullRequestsWithCommit(sha interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPullRequestsWithCommit", reflect.TypeOf((*MockGithubActionIface)(nil).ListPullRequestsWithCommit), sha)
+}
+
+// ListReleaseAssets mocks base method.
+func (m *MockGithubActionIface) ListReleaseAssets(releaseID int64) ([]*github.ReleaseAsset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReleaseAssets", releaseID)
+	ret0, _ := ret[0].([]*github.ReleaseAsset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReleaseAssets indicates an expected call of ListReleaseAssets.
+func (mr *MockGithubActionIfaceMockRecorder) ListReleaseAssets(releaseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReleaseAssets", reflect.TypeOf((*MockGithubActionIface)(nil).ListReleaseAssets), releaseID)
+}
+
+// MoveGithubTag mocks base method.
+func (m *MockGithubActionIface) MoveGithubTag(version, target string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveGithubTag", version, target)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveGithubTag indicates an expected call of MoveGithubTag.
+func (mr *MockGithubActionIfaceMockRecorder) MoveGithubTag(version, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveGithubTag", reflect.TypeOf((*MockGithubActionIface)(nil).MoveGithubTag), version, target)
 }
 
 // ParseGithubEvent mocks base method.
@@ -152,3 +377,63 @@ func (mr *MockGithubActionIfaceMockRecorder) ParseGithubEvent(filePath interface
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseGithubEvent", reflect.TypeOf((*MockGithubActionIface)(nil).ParseGithubEvent), filePath)
 }
+
+// ParseGithubPushEvent mocks base method.
+func (m *MockGithubActionIface) ParseGithubPushEvent(filePath string) (*github.PushEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseGithubPushEvent", filePath)
+	ret0, _ := ret[0].(*github.PushEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseGithubPushEvent indicates an expected call of ParseGithubPushEvent.
+func (mr *MockGithubActionIfaceMockRecorder) ParseGithubPushEvent(filePath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseGithubPushEvent", reflect.TypeOf((*MockGithubActionIface)(nil).ParseGithubPushEvent), filePath)
+}
+
+// ReleaseExists mocks base method.
+func (m *MockGithubActionIface) ReleaseExists(version string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExists", version)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseExists indicates an expected call of ReleaseExists.
+func (mr *MockGithubActionIfaceMockRecorder) ReleaseExists(version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExists", reflect.TypeOf((*MockGithubActionIface)(nil).ReleaseExists), version)
+}
+
+// ResolveSHA mocks base method.
+func (m *MockGithubActionIface) ResolveSHA(ref string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveSHA", ref)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveSHA indicates an expected call of ResolveSHA.
+func (mr *MockGithubActionIfaceMockRecorder) ResolveSHA(ref interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveSHA", reflect.TypeOf((*MockGithubActionIface)(nil).ResolveSHA), ref)
+}
+
+// UploadReleaseAsset mocks base method.
+func (m *MockGithubActionIface) UploadReleaseAsset(releaseID int64, name, path string) (*github.ReleaseAsset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadReleaseAsset", releaseID, name, path)
+	ret0, _ := ret[0].(*github.ReleaseAsset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadReleaseAsset indicates an expected call of UploadReleaseAsset.
+func (mr *MockGithubActionIfaceMockRecorder) UploadReleaseAsset(releaseID, name, path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadReleaseAsset", reflect.TypeOf((*MockGithubActionIface)(nil).UploadReleaseAsset), releaseID, name, path)
+}
@@ -0,0 +1,389 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestGithubActionImpl returns a GithubActionImpl talking to a test
+// server serving handler.
+func newTestGithubActionImpl(t *testing.T, handler http.Handler) *GithubActionImpl {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client.BaseURL = baseURL
+	client.UploadURL = baseURL
+	return &GithubActionImpl{Repository: "owner/repo", GithubClient: client}
+}
+
+func TestGithubGetLatestTagPaginated(t *testing.T) {
+	const pages = 3
+	var requests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/git/matching-refs/tags", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "100", r.URL.Query().Get("per_page"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if page < pages {
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=%d>; rel="next"`, r.URL.Path, page+1))
+		}
+		// the highest tag is only on the last page
+		fmt.Fprintf(w, `[{"ref": "refs/tags/v1.%d.0"}, {"ref": "refs/tags/floating-%d"}]`, page*10, page)
+	})
+	impl := newTestGithubActionImpl(t, mux)
+
+	tag, err := impl.GetGithubLatestTag(">0.0.0", "v%major%.%minor%.%patch%", "")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.30.0", tag)
+	assert.Equal(t, pages, requests)
+}
+
+func TestGithubGetLatestTagReachable(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/git/matching-refs/tags", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"ref": "refs/tags/v1.0.0"}, {"ref": "refs/tags/v1.1.0"}, {"ref": "refs/tags/v2.0.0"}]`)
+	})
+	statuses := map[string]string{"v2.0.0": "diverged", "v1.1.0": "ahead", "v1.0.0": "ahead"}
+	for tag, status := range statuses {
+		body := fmt.Sprintf(`{"status": %q}`, status)
+		mux.HandleFunc("/repos/owner/repo/compare/"+tag+"...abc123", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		})
+	}
+	impl := newTestGithubActionImpl(t, mux)
+
+	tag, err := impl.GetGithubLatestTag(">0.0.0", "v%major%.%minor%.%patch%", "abc123")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.1.0", tag)
+}
+
+func TestGithubGetLatestTagReachableCapped(t *testing.T) {
+	defer func(checks int) { maxReachabilityChecks = checks }(maxReachabilityChecks)
+	maxReachabilityChecks = 2
+
+	var compared []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/git/matching-refs/tags", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"ref": "refs/tags/v1.0.0"}, {"ref": "refs/tags/v2.0.0"}, {"ref": "refs/tags/v3.0.0"}]`)
+	})
+	mux.HandleFunc("/repos/owner/repo/compare/", func(w http.ResponseWriter, r *http.Request) {
+		compared = append(compared, r.URL.Path)
+		fmt.Fprint(w, `{"status": "diverged"}`)
+	})
+	impl := newTestGithubActionImpl(t, mux)
+
+	_, err := impl.GetGithubLatestTag(">0.0.0", "v%major%.%minor%.%patch%", "abc123")
+	assert.ErrorIs(t, err, ErrNoReachableTag)
+	assert.Equal(t, []string{"/repos/owner/repo/compare/v3.0.0...abc123", "/repos/owner/repo/compare/v2.0.0...abc123"}, compared)
+}
+
+func TestGithubCreateTagExists(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/git/refs", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"message": "Reference already exists"}`)
+	})
+	mux.HandleFunc("/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"message": "Validation Failed", "errors": [{"resource": "Release", "code": "already_exists", "field": "tag_name"}]}`)
+	})
+	impl := newTestGithubActionImpl(t, mux)
+
+	assert.ErrorIs(t, impl.CreateGithubTag("v1.0.1", "abc123", TagOptions{}), ErrTagExists)
+	_, err := impl.CreateGithubRelease("v1.0.1", "abc123", ReleaseOptions{})
+	assert.ErrorIs(t, err, ErrTagExists)
+}
+
+func TestGithubCreateReleaseOptions(t *testing.T) {
+	var release github.RepositoryRelease
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		release = github.RepositoryRelease{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&release))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+	impl := newTestGithubActionImpl(t, mux)
+
+	created, err := impl.CreateGithubRelease("v1.1.0-rc.1", "abc123", ReleaseOptions{Draft: true, Prerelease: true, MakeLatest: "false"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created.GetID())
+	assert.True(t, release.GetDraft())
+	assert.True(t, release.GetPrerelease())
+	assert.Equal(t, "false", release.GetMakeLatest())
+
+	assert.True(t, release.GetGenerateReleaseNotes())
+
+	_, err = impl.CreateGithubRelease("v1.1.0", "abc123", ReleaseOptions{Body: "## What's Changed"})
+	require.NoError(t, err)
+	assert.False(t, release.GetDraft())
+	assert.False(t, release.GetPrerelease())
+	assert.Nil(t, release.MakeLatest)
+	assert.Equal(t, "## What's Changed", release.GetBody())
+	assert.False(t, release.GetGenerateReleaseNotes())
+}
+
+func TestGithubChangelogFile(t *testing.T) {
+	var committed github.RepositoryContentFileOptions
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/contents/CHANGELOG.md", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut:
+			committed = github.RepositoryContentFileOptions{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&committed))
+			fmt.Fprint(w, `{"commit": {"sha": "def456"}}`)
+		case r.URL.Query().Get("ref") == "abc123":
+			fmt.Fprint(w, `{"type": "file", "encoding": "base64", "content": "IyBDaGFuZ2Vsb2cK", "sha": "blob1"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		}
+	})
+	impl := newTestGithubActionImpl(t, mux)
+
+	content, blobSHA, err := impl.GetFileContent("CHANGELOG.md", "abc123")
+	require.NoError(t, err)
+	assert.Equal(t, "# Changelog\n", content)
+	assert.Equal(t, "blob1", blobSHA)
+
+	_, _, err = impl.GetFileContent("CHANGELOG.md", "old111")
+	assert.ErrorIs(t, err, ErrFileNotFound)
+
+	sha, err := impl.CommitFile("main", "CHANGELOG.md", "# Changelog\n\n## v1.1.0\n", "chore(release): add v1.1.0 to CHANGELOG.md", "blob1")
+	require.NoError(t, err)
+	assert.Equal(t, "def456", sha)
+	assert.Equal(t, "main", committed.GetBranch())
+	assert.Equal(t, "blob1", committed.GetSHA())
+	assert.Equal(t, "# Changelog\n\n## v1.1.0\n", string(committed.Content))
+}
+
+func TestGithubChangelogPullRequest(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/git/ref/heads/semver-sugar/changelog-v1.1.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"ref": "refs/heads/semver-sugar/changelog-v1.1.0", "object": {"sha": "abc123"}}`)
+	})
+	mux.HandleFunc("/repos/owner/repo/git/ref/heads/semver-sugar/changelog-v1.2.0", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "Not Found"}`)
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "open", r.URL.Query().Get("state"))
+		assert.Equal(t, "main", r.URL.Query().Get("base"))
+		if r.URL.Query().Get("head") == "owner:semver-sugar/changelog-v1.1.0" {
+			fmt.Fprint(w, `[{"number": 8, "html_url": "https://github.com/owner/repo/pull/8"}]`)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	})
+	impl := newTestGithubActionImpl(t, mux)
+
+	exists, err := impl.BranchExists("semver-sugar/changelog-v1.1.0")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = impl.BranchExists("semver-sugar/changelog-v1.2.0")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	url, err := impl.GetOpenPullRequest("semver-sugar/changelog-v1.1.0", "main")
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/owner/repo/pull/8", url)
+	url, err = impl.GetOpenPullRequest("semver-sugar/changelog-v1.2.0", "main")
+	require.NoError(t, err)
+	assert.Empty(t, url)
+}
+
+func TestGithubMoveTag(t *testing.T) {
+	var updated map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/git/refs", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"message": "Reference already exists"}`)
+	})
+	mux.HandleFunc("/repos/owner/repo/git/refs/tags/v1", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+		fmt.Fprint(w, `{"ref": "refs/tags/v1", "object": {"sha": "abc123"}}`)
+	})
+	impl := newTestGithubActionImpl(t, mux)
+
+	require.NoError(t, impl.MoveGithubTag("v1", "abc123"))
+	assert.Equal(t, map[string]any{"sha": "abc123", "force": true}, updated)
+}
+
+func TestGithubCreateAnnotatedTag(t *testing.T) {
+	var tag, ref map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/git/tags", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&tag))
+		fmt.Fprint(w, `{"sha": "tag456"}`)
+	})
+	mux.HandleFunc("/repos/owner/repo/git/refs", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&ref))
+		fmt.Fprint(w, `{"ref": "refs/tags/v1.1.0"}`)
+	})
+	impl := newTestGithubActionImpl(t, mux)
+
+	require.NoError(t, impl.CreateGithubTag("v1.1.0", "abc123", TagOptions{
+		Annotated:   true,
+		Message:     "Release v1.1.0",
+		TaggerName:  "Release Bot",
+		TaggerEmail: "release@example.com",
+		Date:        time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC),
+	}))
+	assert.Equal(t, map[string]any{
+		"tag":     "v1.1.0",
+		"message": "Release v1.1.0",
+		"object":  "abc123",
+		"type":    "commit",
+		"tagger":  map[string]any{"name": "Release Bot", "email": "release@example.com", "date": "2024-10-01T12:00:00Z"},
+	}, tag)
+	assert.Equal(t, map[string]any{"ref": "refs/tags/v1.1.0", "sha": "tag456"}, ref)
+}
+
+func TestGithubUploadReleaseAsset(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"manifest.json": `{"version": "1.1.0"}`,
+		"SHA256SUMS":    "0123  app.tar.gz\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	contentTypes := map[string]string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/releases/1/assets", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			name := r.URL.Query().Get("name")
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, files[name], string(body))
+			contentTypes[name] = r.Header.Get("Content-Type")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id": 7, "name": %q}`, name)
+		case r.URL.Query().Get("page") == "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=2>; rel="next"`, r.URL.Path))
+			fmt.Fprint(w, `[{"id": 7, "name": "manifest.json"}]`)
+		default:
+			fmt.Fprint(w, `[{"id": 8, "name": "SHA256SUMS"}]`)
+		}
+	})
+	mux.HandleFunc("/repos/owner/repo/releases/assets/7", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	impl := newTestGithubActionImpl(t, mux)
+
+	for name := range files {
+		asset, err := impl.UploadReleaseAsset(1, name, filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, name, asset.GetName())
+	}
+	assert.Equal(t, "application/json", contentTypes["manifest.json"])
+	assert.Equal(t, "text/plain; charset=utf-8", contentTypes["SHA256SUMS"])
+	assets, err := impl.ListReleaseAssets(1)
+	require.NoError(t, err)
+	require.Len(t, assets, 2)
+	assert.Equal(t, "SHA256SUMS", assets[1].GetName())
+	assert.NoError(t, impl.DeleteReleaseAsset(7))
+
+	_, err = impl.UploadReleaseAsset(1, "missing", filepath.Join(dir, "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestGithubGetRelease(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/releases/tags/v1.1.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 3, "tag_name": "v1.1.0", "assets": [{"id": 7, "name": "app.tar.gz"}]}`)
+	})
+	impl := newTestGithubActionImpl(t, mux)
+
+	release, err := impl.GetGithubRelease("v1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), release.GetID())
+	require.Len(t, release.Assets, 1)
+	assert.Equal(t, "app.tar.gz", release.Assets[0].GetName())
+}
+
+func TestGithubGetDraftRelease(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/releases/tags/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "Not Found"}`)
+	})
+	mux.HandleFunc("/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=2>; rel="next"`, r.URL.Path))
+			fmt.Fprint(w, `[{"id": 4, "tag_name": "v1.2.0", "draft": true}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"id": 3, "tag_name": "v1.1.0", "draft": true}]`)
+	})
+	impl := newTestGithubActionImpl(t, mux)
+
+	exists, err := impl.ReleaseExists("v1.1.0")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	release, err := impl.GetGithubRelease("v1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), release.GetID())
+	assert.True(t, release.GetDraft())
+
+	exists, err = impl.ReleaseExists("v1.3.0")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = impl.GetGithubRelease("v1.3.0")
+	assert.ErrorIs(t, err, ErrReleaseNotFound)
+}
+
+func TestGithubListPathCommits(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/compare/v1.0.0...abc123", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"commits": [
+			{"sha": "c1", "commit": {"committer": {"date": "2024-01-02T00:00:00Z"}}},
+			{"sha": "c2", "commit": {"committer": {"date": "2024-01-01T00:00:00Z"}}},
+			{"sha": "c3", "commit": {"committer": {"date": "2024-01-03T00:00:00Z"}}}
+		]}`)
+	})
+	mux.HandleFunc("/repos/owner/repo/commits", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "abc123", r.URL.Query().Get("sha"))
+		assert.Equal(t, "libs/auth", r.URL.Query().Get("path"))
+		if r.URL.Query().Get("page") == "" {
+			assert.Equal(t, "2024-01-01T00:00:00Z", r.URL.Query().Get("since"))
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=2>; rel="next"`, r.URL.Path))
+			// c0 changed the path before v1.0.0
+			fmt.Fprint(w, `[{"sha": "c3"}, {"sha": "c0"}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"sha": "c1"}]`)
+	})
+	impl := newTestGithubActionImpl(t, mux)
+
+	commits, err := impl.ListPathCommits("v1.0.0", "abc123", "libs/auth")
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, "c1", commits[0].GetSHA())
+	assert.Equal(t, "c3", commits[1].GetSHA())
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withRemote adds a bare clone of the repository of impl as its origin.
+func withRemote(t *testing.T, impl *GitActionImpl) {
+	t.Helper()
+	remote := t.TempDir()
+	for _, args := range [][]string{
+		{"clone", "--quiet", "--bare", impl.Dir, remote},
+		{"remote", "add", "origin", remote},
+	} {
+		_, err := impl.git(args...)
+		require.NoError(t, err)
+	}
+	impl.Remote = "origin"
+}
+
+func requireTool(t *testing.T, name string) {
+	t.Helper()
+	if _, err := exec.LookPath(name); err != nil {
+		t.Skipf("%s is not installed", name)
+	}
+}
+
+func TestGitCreateSSHSignedTag(t *testing.T) {
+	requireTool(t, "ssh-keygen")
+	keyFile := filepath.Join(t.TempDir(), "id_ed25519")
+	_, err := runWithEnv(nil, "", "ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "release", "-f", keyFile)
+	require.NoError(t, err)
+	key, err := os.ReadFile(keyFile)
+	require.NoError(t, err)
+
+	impl := newTestRepository(t)
+	withRemote(t, impl)
+	impl.SigningFormat = SigningFormatSSH
+	impl.SigningKey = string(key)
+	head, err := impl.ResolveSHA("HEAD")
+	require.NoError(t, err)
+
+	options := TagOptions{Annotated: true, Sign: true, Message: "Release v1.1.0"}
+	require.NoError(t, impl.CreateGithubTag("v1.1.0", head, options))
+	contents, err := impl.git("cat-file", "tag", "v1.1.0")
+	require.NoError(t, err)
+	assert.Contains(t, contents, "-----BEGIN SSH SIGNATURE-----")
+	sha, err := impl.GetTagSHA("v1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, head, sha)
+
+	// the path of a key works as well
+	impl.SigningKey = keyFile
+	require.NoError(t, impl.CreateGithubTag("v1.2.0", head, options))
+}
+
+func TestGitCreateGPGSignedTag(t *testing.T) {
+	requireTool(t, "gpg")
+	home, err := os.MkdirTemp("", "gpg-test-")
+	require.NoError(t, err)
+	env := []string{"GNUPGHOME=" + home}
+	t.Cleanup(func() { tagSigning{dir: home, env: env}.cleanup() })
+	_, err = runWithEnv(env, "", "gpg", "--batch", "--passphrase", "", "--quick-generate-key", "Release <release@example.com>", "ed25519", "sign", "never")
+	require.NoError(t, err)
+	key, err := runWithEnv(env, "", "gpg", "--batch", "--armor", "--export-secret-keys")
+	require.NoError(t, err)
+
+	impl := newTestRepository(t)
+	withRemote(t, impl)
+	impl.SigningFormat = SigningFormatGPG
+	impl.SigningKey = key
+	head, err := impl.ResolveSHA("HEAD")
+	require.NoError(t, err)
+
+	require.NoError(t, impl.CreateGithubTag("v1.1.0", head, TagOptions{Annotated: true, Sign: true, Message: "Release v1.1.0"}))
+	contents, err := impl.git("cat-file", "tag", "v1.1.0")
+	require.NoError(t, err)
+	assert.Contains(t, contents, "-----BEGIN PGP SIGNATURE-----")
+}
+
+func TestGitSignedTagInvalidKey(t *testing.T) {
+	impl := newTestRepository(t)
+	impl.SigningFormat = SigningFormatSSH
+	impl.SigningKey = filepath.Join(t.TempDir(), "missing")
+
+	assert.Error(t, impl.CreateGithubTag("v1.1.0", "HEAD", TagOptions{Annotated: true, Sign: true}))
+	_, err := impl.GetTagSHA("v1.1.0")
+	assert.ErrorIs(t, err, ErrTagNotFound)
+}
+
+func TestGithubSignedTagNotSupported(t *testing.T) {
+	impl := &GithubActionImpl{Repository: "owner/repo"}
+	assert.ErrorIs(t, impl.CreateGithubTag("v1.1.0", "abc123", TagOptions{Annotated: true, Sign: true}), ErrSigningNotSupported)
+}
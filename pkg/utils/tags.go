@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/actions-go/toolkit/core"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+)
+
+var (
+	ErrNoMatchingTag = errors.New("no matching tag found")
+	ErrTagNotFound   = errors.New("tag not found")
+	// ErrTagExists is returned when creating a tag or release fails because
+	// its tag was created in the meantime, e.g. by a concurrent run.
+	ErrTagExists = errors.New("tag already exists")
+	// ErrNoReachableTag is returned when none of the highest matching tags
+	// checked is reachable from the release SHA.
+	ErrNoReachableTag = errors.New("no reachable tag found")
+)
+
+// maxReachabilityChecks caps the tags latestTag checks with isAncestor, each
+// check is an API call with the github backend.
+var maxReachabilityChecks = 50
+
+// latestTag returns the highest of tags written with tagFormat whose version
+// satisfies versionRange. When reachableFrom is set, only tags isAncestor
+// reports as ancestors of that commit are considered, so every release line
+// finds its own latest tag. Only the maxReachabilityChecks highest tags are
+// checked, a narrower versionRange skips the tags of other release lines.
+func latestTag(tags []string, versionRange, tagFormat, reachableFrom string, isAncestor func(tag, sha string) (bool, error)) (string, error) {
+	matching, err := semver.FilterTags(tags, tagFormat, versionRange)
+	if err != nil {
+		return "", err
+	}
+	for i, tag := range matching {
+		if reachableFrom == "" {
+			return tag, nil
+		}
+		if i == maxReachabilityChecks {
+			return "", fmt.Errorf("%w: the %d highest tags matching %s are not reachable from %s, narrow the version range", ErrNoReachableTag, i, versionRange, reachableFrom)
+		}
+		reachable, err := isAncestor(tag, reachableFrom)
+		if err != nil {
+			return "", err
+		}
+		if reachable {
+			return tag, nil
+		}
+		core.Debug("Skipping tag " + tag + ", it is not reachable from " + reachableFrom)
+	}
+	return "", ErrNoMatchingTag
+}
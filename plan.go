@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/actions-go/toolkit/core"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/semver"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+)
+
+// releasePlan describes what executeCreateRelease would do for a release.
+type releasePlan struct {
+	Component         string `json:"component,omitempty"`
+	Strategy          string `json:"strategy"`
+	PreviousTag       string `json:"previous_tag"`
+	Tag               string `json:"tag"`
+	Increment         string `json:"increment"`
+	TargetSHA         string `json:"target_sha"`
+	ReleaseNotesRange string `json:"release_notes_range,omitempty"`
+	// Release holds the options of the created release, release strategy
+	// only.
+	Release *utils.ReleaseOptions `json:"release,omitempty"`
+	Skipped bool                  `json:"skipped"`
+	Steps   []string              `json:"steps"`
+}
+
+func newReleasePlan(actionConfig ActionConfig, isSkipRelease bool) (releasePlan, error) {
+	plan := releasePlan{
+		Strategy:    actionConfig.ReleaseStrategy,
+		PreviousTag: actionConfig.CurrentTag,
+		Tag:         actionConfig.NextTag,
+		Increment:   actionConfig.Increment,
+		TargetSHA:   actionConfig.CustomReleaseSHA,
+		Skipped:     isSkipRelease,
+		Steps:       []string{},
+	}
+	if isSkipRelease || actionConfig.ReleaseStrategy == ReleaseStrategyNone {
+		return plan, nil
+	}
+
+	target := plan.TargetSHA
+	switch actionConfig.Changelog {
+	case ChangelogPush:
+		plan.Steps = append(plan.Steps, fmt.Sprintf("commit %s of %s to %s", actionConfig.ChangelogFile, plan.Tag, actionConfig.ReleaseBranch))
+		target = "the changelog commit"
+	case ChangelogPullRequest:
+		plan.Steps = append(plan.Steps, fmt.Sprintf("open pull request adding %s to %s", plan.Tag, actionConfig.ChangelogFile))
+	}
+
+	switch actionConfig.ReleaseStrategy {
+	case ReleaseStrategyRelease:
+		options := releaseOptions(actionConfig)
+		plan.Release = &options
+		plan.ReleaseNotesRange = fmt.Sprintf("%s...%s", plan.PreviousTag, plan.Tag)
+		kind := "release"
+		if options.Prerelease {
+			kind = "pre-release"
+		}
+		if options.Draft {
+			kind = "draft " + kind
+		}
+		if actionConfig.TagType == TagTypeAnnotated || actionConfig.TagType == TagTypeSigned {
+			plan.Steps = append(plan.Steps, fmt.Sprintf("create %s tag %s at %s", actionConfig.TagType, plan.Tag, target))
+		}
+		plan.Steps = append(plan.Steps,
+			fmt.Sprintf("create %s %s targeting %s", kind, plan.Tag, target),
+			fmt.Sprintf("generate release notes for %s", plan.ReleaseNotesRange),
+		)
+		plan.Steps = append(plan.Steps, assetSteps(actionConfig, plan.Tag)...)
+	case ReleaseStrategyTag:
+		kind := "tag"
+		if actionConfig.TagType != "" && actionConfig.TagType != TagTypeLightweight {
+			kind = actionConfig.TagType + " tag"
+		}
+		plan.Steps = append(plan.Steps, fmt.Sprintf("create %s %s at %s", kind, plan.Tag, target))
+	default:
+		return releasePlan{}, fmt.Errorf("invalid release strategy %q", actionConfig.ReleaseStrategy)
+	}
+	plan.Steps = append(plan.Steps, floatingTagSteps(actionConfig, target)...)
+	return plan, nil
+}
+
+func assetSteps(actionConfig ActionConfig, tag string) []string {
+	if len(actionConfig.Assets) == 0 {
+		return nil
+	}
+	step := fmt.Sprintf("upload assets matching %s to %s", strings.Join(actionConfig.Assets, ", "), tag)
+	if actionConfig.AssetChecksums {
+		step += " with " + ChecksumsFile
+	}
+	return []string{step}
+}
+
+func floatingTagSteps(actionConfig ActionConfig, target string) []string {
+	nextVersion, err := semver.ParseTag(actionConfig.TagFormat, actionConfig.NextTag)
+	if err != nil || !movesFloatingTags(actionConfig, nextVersion) {
+		return nil
+	}
+	var steps []string
+	for _, format := range actionConfig.FloatingTags {
+		steps = append(steps, fmt.Sprintf("move floating tag %s to %s", nextVersion.Format(format), target))
+	}
+	return steps
+}
+
+// executeDryRun reports the release plan instead of releasing.
+func executeDryRun(actionConfig ActionConfig, isSkipRelease bool) error {
+	plan, err := newReleasePlan(actionConfig, isSkipRelease)
+	if err != nil {
+		return err
+	}
+	planJSON, err := json.Marshal(plan)
+	if err != nil {
+		return err
+	}
+
+	core.Info("Dry run, nothing is released. Release plan:")
+	core.Infof("  strategy: %s", plan.Strategy)
+	core.Infof("  tag: %s -> %s (%s)", plan.PreviousTag, plan.Tag, plan.Increment)
+	core.Infof("  target SHA: %s", plan.TargetSHA)
+	if plan.Skipped {
+		core.Info("  skipped because of skip-release label")
+	}
+	for _, step := range plan.Steps {
+		core.Info("  would " + step)
+	}
+	core.SetOutput("plan", string(planJSON))
+	return nil
+}
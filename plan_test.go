@@ -0,0 +1,126 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReleasePlan(t *testing.T) {
+	actionConfig := ActionConfig{
+		CurrentTag:       "v1.0.0",
+		NextTag:          "v1.1.0",
+		Increment:        "minor",
+		CustomReleaseSHA: "abc123",
+		ReleaseBranch:    "main",
+		ChangelogFile:    "CHANGELOG.md",
+	}
+
+	tests := []struct {
+		name          string
+		strategy      string
+		changelog     string
+		isSkipRelease bool
+		expectedPlan  releasePlan
+	}{
+		{
+			name:     "Release strategy Release",
+			strategy: ReleaseStrategyRelease,
+			expectedPlan: releasePlan{
+				Strategy:          ReleaseStrategyRelease,
+				PreviousTag:       "v1.0.0",
+				Tag:               "v1.1.0",
+				Increment:         "minor",
+				TargetSHA:         "abc123",
+				ReleaseNotesRange: "v1.0.0...v1.1.0",
+				Release:           &utils.ReleaseOptions{},
+				Steps: []string{
+					"create release v1.1.0 targeting abc123",
+					"generate release notes for v1.0.0...v1.1.0",
+				},
+			},
+		},
+		{
+			name:     "Release strategy Tag",
+			strategy: ReleaseStrategyTag,
+			expectedPlan: releasePlan{
+				Strategy:    ReleaseStrategyTag,
+				PreviousTag: "v1.0.0",
+				Tag:         "v1.1.0",
+				Increment:   "minor",
+				TargetSHA:   "abc123",
+				Steps:       []string{"create tag v1.1.0 at abc123"},
+			},
+		},
+		{
+			name:      "Changelog push",
+			strategy:  ReleaseStrategyTag,
+			changelog: ChangelogPush,
+			expectedPlan: releasePlan{
+				Strategy:    ReleaseStrategyTag,
+				PreviousTag: "v1.0.0",
+				Tag:         "v1.1.0",
+				Increment:   "minor",
+				TargetSHA:   "abc123",
+				Steps: []string{
+					"commit CHANGELOG.md of v1.1.0 to main",
+					"create tag v1.1.0 at the changelog commit",
+				},
+			},
+		},
+		{
+			name:          "Skip release",
+			strategy:      ReleaseStrategyTag,
+			isSkipRelease: true,
+			expectedPlan: releasePlan{
+				Strategy:    ReleaseStrategyTag,
+				PreviousTag: "v1.0.0",
+				Tag:         "v1.1.0",
+				Increment:   "minor",
+				TargetSHA:   "abc123",
+				Skipped:     true,
+				Steps:       []string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionConfig.ReleaseStrategy = tt.strategy
+			actionConfig.Changelog = tt.changelog
+			plan, err := newReleasePlan(actionConfig, tt.isSkipRelease)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPlan, plan)
+		})
+	}
+
+	actionConfig.ReleaseStrategy = "relase"
+	_, err := newReleasePlan(actionConfig, false)
+	assert.EqualError(t, err, `invalid release strategy "relase"`)
+}
+
+func TestNewReleasePlanAssets(t *testing.T) {
+	actionConfig := ActionConfig{
+		ReleaseStrategy:  ReleaseStrategyRelease,
+		CurrentTag:       "v1.0.0",
+		NextTag:          "v1.1.0",
+		CustomReleaseSHA: "abc123",
+		Assets:           []string{"dist/*.tar.gz", "dist/*.zip"},
+		AssetChecksums:   true,
+	}
+
+	plan, err := newReleasePlan(actionConfig, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"create release v1.1.0 targeting abc123",
+		"generate release notes for v1.0.0...v1.1.0",
+		"upload assets matching dist/*.tar.gz, dist/*.zip to v1.1.0 with SHA256SUMS",
+	}, plan.Steps)
+
+	actionConfig.AssetChecksums = false
+	plan, err = newReleasePlan(actionConfig, false)
+	require.NoError(t, err)
+	assert.Equal(t, "upload assets matching dist/*.tar.gz, dist/*.zip to v1.1.0", plan.Steps[2])
+}
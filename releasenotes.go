@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/actions-go/toolkit/core"
+	github "github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/notes"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ReleaseNotesGithub lets GitHub generate the release notes.
+	ReleaseNotesGithub = "github"
+	// ReleaseNotesTemplate renders the release notes from NotesTemplate.
+	ReleaseNotesTemplate = "template"
+)
+
+var ErrInvalidReleaseNotesSections = errors.New("invalid release notes sections")
+
+// ParseReleaseNotesSections parses the YAML list of the
+// release_notes_sections input, e.g.
+//
+//   - title: Features
+//     labels: ["minor", "type/feature*"]
+//     types: ["feat"]
+//   - title: Other Changes
+func ParseReleaseNotesSections(input string) ([]notes.Section, error) {
+	var sections []notes.Section
+	decoder := yaml.NewDecoder(strings.NewReader(input))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&sections); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidReleaseNotesSections, err)
+	}
+	if err := validateReleaseNotesSections(sections); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
+
+func validateReleaseNotesSections(sections []notes.Section) error {
+	for i, section := range sections {
+		if section.Title == "" {
+			return fmt.Errorf("%w: section %d needs a title", ErrInvalidReleaseNotesSections, i+1)
+		}
+	}
+	return nil
+}
+
+// executeReleaseContent renders the release notes and updates the changelog
+// of actionConfig's next tag from the same commits and pull requests. It
+// returns the options of the release, with the rendered release notes as
+// body when they come from a template, and actionConfig targeting the
+// changelog commit when one was pushed.
+func executeReleaseContent(ghActionIface utils.GithubActionIface, actionConfig ActionConfig) (ActionConfig, utils.ReleaseOptions, error) {
+	options := releaseOptions(actionConfig)
+	var err error
+	options.Tag, err = tagOptions(actionConfig)
+	if err != nil {
+		return actionConfig, options, err
+	}
+	templated := actionConfig.ReleaseStrategy == ReleaseStrategyRelease && actionConfig.ReleaseNotes == ReleaseNotesTemplate
+	changelog := actionConfig.Changelog != "" && actionConfig.Changelog != ChangelogNone && actionConfig.ReleaseStrategy != ReleaseStrategyNone
+	if !templated && !changelog {
+		return actionConfig, options, nil
+	}
+
+	commits, pullRequests, err := releaseNotesInput(ghActionIface, actionConfig)
+	if err != nil {
+		return actionConfig, options, err
+	}
+	newData := func(sections []notes.Section) notes.Data {
+		data := notes.NewData(actionConfig.CurrentTag, actionConfig.NextTag, actionConfig.Increment, commits, pullRequests, sections)
+		data.Date = formatContext(actionConfig).Date
+		return data
+	}
+	if templated {
+		core.Debug("Rendering release notes now")
+		options.Body, err = notes.Render(actionConfig.NotesTemplate, newData(actionConfig.NotesSections))
+		if err != nil {
+			return actionConfig, options, err
+		}
+	}
+	if changelog {
+		actionConfig, err = executeChangelog(ghActionIface, actionConfig, newData(notes.DefaultChangelogSections))
+	}
+	return actionConfig, options, err
+}
+
+// releaseNotesInput collects the commits between the latest and the next tag
+// and the pull requests they were merged with.
+func releaseNotesInput(ghActionIface utils.GithubActionIface, actionConfig ActionConfig) ([]notes.Commit, []notes.PullRequest, error) {
+	repositoryCommits, err := listReleaseCommits(ghActionIface, actionConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	var commits []notes.Commit
+	var pullRequests []notes.PullRequest
+	seen := map[int]bool{}
+	for _, repositoryCommit := range repositoryCommits {
+		commit := notes.Commit{
+			SHA:     repositoryCommit.GetSHA(),
+			Message: repositoryCommit.GetCommit().GetMessage(),
+			Author:  repositoryCommit.GetAuthor().GetLogin(),
+		}
+		if commit.Author == "" {
+			commit.Author = repositoryCommit.GetCommit().GetAuthor().GetName()
+		}
+		pullRequest, err := mergedPullRequest(ghActionIface, commit.SHA, actionConfig.ReleaseBranch)
+		if err != nil {
+			return nil, nil, err
+		}
+		if pullRequest != nil {
+			commit.PullRequest = pullRequest.GetNumber()
+			if !seen[commit.PullRequest] {
+				seen[commit.PullRequest] = true
+				pullRequests = append(pullRequests, newNotesPullRequest(pullRequest))
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits, pullRequests, nil
+}
+
+// pullRequestCache remembers the pull requests of commits, so retries of a
+// release and releases of several components look up every commit once.
+type pullRequestCache struct {
+	utils.GithubActionIface
+	pullRequests map[string][]*github.PullRequest
+}
+
+func newPullRequestCache(ghActionIface utils.GithubActionIface) pullRequestCache {
+	return pullRequestCache{GithubActionIface: ghActionIface, pullRequests: map[string][]*github.PullRequest{}}
+}
+
+func (c pullRequestCache) ListPullRequestsWithCommit(sha string) ([]*github.PullRequest, error) {
+	if pullRequests, ok := c.pullRequests[sha]; ok {
+		return pullRequests, nil
+	}
+	pullRequests, err := c.GithubActionIface.ListPullRequestsWithCommit(sha)
+	if err == nil {
+		c.pullRequests[sha] = pullRequests
+	}
+	return pullRequests, err
+}
+
+func newNotesPullRequest(pullRequest *github.PullRequest) notes.PullRequest {
+	var labels []string
+	for _, label := range pullRequest.Labels {
+		labels = append(labels, label.GetName())
+	}
+	return notes.PullRequest{
+		Number: pullRequest.GetNumber(),
+		Title:  pullRequest.GetTitle(),
+		Author: pullRequest.GetUser().GetLogin(),
+		URL:    pullRequest.GetHTMLURL(),
+		Labels: labels,
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	github "github.com/google/go-github/v65/github"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/notes"
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReleaseNotesSections(t *testing.T) {
+	sections, err := ParseReleaseNotesSections(`
+- title: Features
+  labels: ["minor"]
+  types: [feat]
+- title: Other Changes
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []notes.Section{
+		{Title: "Features", Labels: []string{"minor"}, Types: []string{"feat"}},
+		{Title: "Other Changes"},
+	}, sections)
+
+	_, err = ParseReleaseNotesSections("- labels: [minor]\n")
+	assert.ErrorIs(t, err, ErrInvalidReleaseNotesSections)
+
+	_, err = ParseReleaseNotesSections("- title: Features\n  label: [minor]\n")
+	assert.ErrorIs(t, err, ErrInvalidReleaseNotesSections)
+}
+
+func TestExecuteReleaseContentTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+	actionConfig := ActionConfig{
+		ReleaseBranch:    "main",
+		ReleaseStrategy:  ReleaseStrategyRelease,
+		ReleaseNotes:     ReleaseNotesTemplate,
+		NotesTemplate:    "{{ if .Date.IsZero }}no date\n{{ end }}{{ range .Sections }}{{ .Title }}:{{ range .Entries }} {{ .Title }} ({{ .Author }});{{ end }}\n{{ end }}",
+		CurrentTag:       "v1.0.0",
+		NextTag:          "v1.1.0",
+		CustomReleaseSHA: "abc123",
+	}
+
+	mockGHActionIface.EXPECT().ListCommits("v1.0.0", "abc123").Return([]*github.RepositoryCommit{
+		{SHA: github.String("aaa111"), Commit: &github.Commit{Message: github.String("feat: add flag")}, Author: &github.User{Login: github.String("alice")}},
+		{SHA: github.String("bbb222"), Commit: &github.Commit{Message: github.String("fix: typo"), Author: &github.CommitAuthor{Name: github.String("Bob")}}},
+	}, nil)
+	mockGHActionIface.EXPECT().ListPullRequestsWithCommit("aaa111").Return([]*github.PullRequest{
+		{Number: github.Int(7), Title: github.String("Add flag"), User: &github.User{Login: github.String("alice")}, MergedAt: &github.Timestamp{Time: time.Now()}, Base: &github.PullRequestBranch{Ref: github.String("main")}, Labels: []*github.Label{{Name: github.String("minor")}}},
+	}, nil)
+	mockGHActionIface.EXPECT().ListPullRequestsWithCommit("bbb222").Return(nil, nil)
+
+	_, options, err := executeReleaseContent(mockGHActionIface, actionConfig)
+	require.NoError(t, err)
+	assert.Equal(t, "Features: Add flag (alice);\nFixes: fix: typo (Bob);\n", options.Body)
+
+	// GitHub generates the notes, nothing to render
+	actionConfig.ReleaseNotes = ReleaseNotesGithub
+	_, options, err = executeReleaseContent(mockGHActionIface, actionConfig)
+	require.NoError(t, err)
+	assert.Empty(t, options.Body)
+}
+
+func TestPullRequestCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGHActionIface := utils.NewMockGithubActionIface(ctrl)
+	pullRequests := []*github.PullRequest{{Number: github.Int(7)}}
+	mockGHActionIface.EXPECT().ListPullRequestsWithCommit("aaa111").Return(pullRequests, nil).Times(1)
+	mockGHActionIface.EXPECT().ListPullRequestsWithCommit("bbb222").Return(nil, errors.New("api error")).Times(2)
+
+	cache := newPullRequestCache(mockGHActionIface)
+	for i := 0; i < 2; i++ {
+		cached, err := cache.ListPullRequestsWithCommit("aaa111")
+		require.NoError(t, err)
+		assert.Equal(t, pullRequests, cached)
+
+		// failed lookups are not cached
+		_, err = cache.ListPullRequestsWithCommit("bbb222")
+		assert.Error(t, err)
+	}
+}
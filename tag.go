@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+)
+
+// DefaultTagMessage is the message of annotated tags when no tag_message is
+// configured.
+const DefaultTagMessage = "{{ .Tag }}"
+
+// tagMessageData is what the tag_message template is rendered with.
+type tagMessageData struct {
+	Tag         string
+	PreviousTag string
+	Increment   string
+	SHA         string
+	Date        time.Time
+}
+
+func parseTagMessage(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultTagMessage
+	}
+	return template.New("tag_message").Option("missingkey=error").Parse(text)
+}
+
+// renderTagMessage renders the tag_message template with data.
+func renderTagMessage(text string, data tagMessageData) (string, error) {
+	tmpl, err := parseTagMessage(text)
+	if err != nil {
+		return "", err
+	}
+	var message strings.Builder
+	if err := tmpl.Execute(&message, data); err != nil {
+		return "", err
+	}
+	return message.String(), nil
+}
+
+// validateTagMessage checks that the tag_message template parses and only
+// uses the available data.
+func validateTagMessage(text string) error {
+	if _, err := renderTagMessage(text, tagMessageData{}); err != nil {
+		return fmt.Errorf("tag_message: %w", err)
+	}
+	return nil
+}
+
+// validateTagger checks that tagger_name and tagger_email are set together,
+// the tag API rejects a tagger with only one of them.
+func validateTagger(actionConfig ActionConfig) error {
+	if (actionConfig.TaggerName == "") != (actionConfig.TaggerEmail == "") {
+		return errors.New("tagger_name, tagger_email: set both or neither")
+	}
+	return nil
+}
+
+// tagOptions returns the options of the tag of actionConfig's next tag.
+func tagOptions(actionConfig ActionConfig) (utils.TagOptions, error) {
+	if actionConfig.TagType != TagTypeAnnotated && actionConfig.TagType != TagTypeSigned {
+		return utils.TagOptions{}, nil
+	}
+	date := formatContext(actionConfig).Date
+	message, err := renderTagMessage(actionConfig.TagMessage, tagMessageData{
+		Tag:         actionConfig.NextTag,
+		PreviousTag: actionConfig.CurrentTag,
+		Increment:   actionConfig.Increment,
+		SHA:         actionConfig.CustomReleaseSHA,
+		Date:        date,
+	})
+	if err != nil {
+		return utils.TagOptions{}, fmt.Errorf("tag_message: %w", err)
+	}
+	return utils.TagOptions{
+		Annotated:   true,
+		Message:     message,
+		TaggerName:  actionConfig.TaggerName,
+		TaggerEmail: actionConfig.TaggerEmail,
+		Date:        date,
+		Sign:        actionConfig.TagType == TagTypeSigned,
+	}, nil
+}
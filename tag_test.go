@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mikolajmikolajczyk/semver-sugar/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagOptions(t *testing.T) {
+	actionConfig := ActionConfig{
+		TagType:          TagTypeLightweight,
+		CurrentTag:       "v1.0.0",
+		NextTag:          "v1.1.0",
+		Increment:        "minor",
+		CustomReleaseSHA: "abc123",
+		TaggerName:       "Release Bot",
+		TaggerEmail:      "release@example.com",
+	}
+
+	options, err := tagOptions(actionConfig)
+	require.NoError(t, err)
+	assert.False(t, options.Annotated)
+
+	actionConfig.TagType = TagTypeAnnotated
+	options, err = tagOptions(actionConfig)
+	require.NoError(t, err)
+	assert.True(t, options.Annotated)
+	assert.Equal(t, "v1.1.0", options.Message)
+	assert.Equal(t, "Release Bot", options.TaggerName)
+	assert.Equal(t, "release@example.com", options.TaggerEmail)
+	assert.False(t, options.Date.IsZero())
+
+	actionConfig.TagMessage = "Release {{ .Tag }} ({{ .Increment }} after {{ .PreviousTag }}) at {{ .SHA }}"
+	options, err = tagOptions(actionConfig)
+	require.NoError(t, err)
+	assert.Equal(t, "Release v1.1.0 (minor after v1.0.0) at abc123", options.Message)
+	assert.False(t, options.Sign)
+
+	actionConfig.TagType = TagTypeSigned
+	options, err = tagOptions(actionConfig)
+	require.NoError(t, err)
+	assert.True(t, options.Annotated)
+	assert.True(t, options.Sign)
+}
+
+func TestValidateSigning(t *testing.T) {
+	actionConfig := defaultActionConfig()
+	actionConfig.TagType = TagTypeSigned
+	assert.ErrorContains(t, validateSigning(actionConfig), "need the git backend")
+
+	actionConfig.Backend = BackendGit
+	assert.NoError(t, validateSigning(actionConfig))
+
+	actionConfig.SigningFormat = utils.SigningFormatSSH
+	assert.ErrorContains(t, validateSigning(actionConfig), "signing_key: required")
+
+	actionConfig.SigningKey = "~/.ssh/release"
+	assert.NoError(t, validateSigning(actionConfig))
+}
+
+func TestValidateTagger(t *testing.T) {
+	actionConfig := defaultActionConfig()
+	assert.NoError(t, validateTagger(actionConfig))
+
+	actionConfig.TaggerName = "release-bot"
+	assert.ErrorContains(t, validateTagger(actionConfig), "set both or neither")
+
+	actionConfig.TaggerEmail = "release-bot@example.com"
+	assert.NoError(t, validateTagger(actionConfig))
+
+	actionConfig.TaggerName = ""
+	assert.ErrorContains(t, validateTagger(actionConfig), "set both or neither")
+}
+
+func TestValidateTagMessage(t *testing.T) {
+	assert.NoError(t, validateTagMessage(""))
+	assert.NoError(t, validateTagMessage("{{ .Tag }} on {{ .Date.Format \"2006-01-02\" }}"))
+	assert.Error(t, validateTagMessage("{{ .Tag "))
+	assert.Error(t, validateTagMessage("{{ .Version }}"))
+}